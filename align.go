@@ -0,0 +1,248 @@
+package pp
+
+import (
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// numberColumn contains the widths used to align numbers printed on
+// different lines on their decimal point: the width of the integer part and
+// the width of the fractional part including the decimal point.
+type numberColumn struct {
+	intWidth  int
+	fracWidth int
+}
+
+// rowAlignment contains the columns of the scalar fields of structures
+// printed inline as elements of a sequence, so that each field is aligned
+// with the same field in the other elements. Numbers are aligned on their
+// decimal point and other values on the left.
+type rowAlignment struct {
+	structType reflect.Type
+	columns    map[string]*numberColumn
+}
+
+func numberWidths(s string, numeric bool) (int, int) {
+	if !numeric {
+		return 0, utf8.RuneCountInString(s)
+	}
+
+	i := strings.IndexByte(s, '.')
+	if i < 0 {
+		return utf8.RuneCountInString(s), 0
+	}
+
+	return utf8.RuneCountInString(s[:i]), utf8.RuneCountInString(s[i:])
+}
+
+func (c *numberColumn) add(s string, numeric bool) {
+	intWidth, fracWidth := numberWidths(s, numeric)
+
+	c.intWidth = max(c.intWidth, intWidth)
+	c.fracWidth = max(c.fracWidth, fracWidth)
+}
+
+// padding returns the number of spaces to print before and after a value to
+// align it in the column.
+func (c *numberColumn) padding(s string, numeric bool) (int, int) {
+	intWidth, fracWidth := numberWidths(s, numeric)
+	return c.intWidth - intWidth, c.fracWidth - fracWidth
+}
+
+func numericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// numericFieldString returns the inline representation of the value of a
+// structure field and whether it is a number which can be aligned on its
+// decimal point. Numbers printed with a type, by a formatter, in another base
+// or as a byte size are not aligned.
+func (p *Printer) numericFieldString(f structField) (string, bool) {
+	s := p.plainFieldString(f)
+
+	if f.redaction != "" || !numericKind(f.value.Kind()) {
+		return s, false
+	}
+
+	return s, p.decimalNumber(s)
+}
+
+// decimalNumber returns whether a string is a decimal number, possibly with
+// thousands separators in its integer part.
+func (p *Printer) decimalNumber(s string) bool {
+	digits := func(s string, separators bool) bool {
+		if s == "" || s[0] < '0' || s[0] > '9' {
+			return false
+		}
+
+		for _, c := range s {
+			if (c < '0' || c > '9') && (!separators || c != p.thousandsSeparator) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	intPart, fracPart, found := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+
+	return digits(intPart, true) && (!found || digits(fracPart, false))
+}
+
+func scalarField(f structField) bool {
+	if f.redaction != "" {
+		return false
+	}
+
+	switch k := f.value.Kind(); k {
+	case reflect.Bool, reflect.String:
+		return true
+	default:
+		return numericKind(k)
+	}
+}
+
+// plainFieldString returns the inline representation of the value of a
+// structure field without colors.
+func (p *Printer) plainFieldString(f structField) string {
+	p2 := p.clone()
+	p2.buf = nil
+	p2.inline = true
+	p2.colors = false
+	p2.printFieldValue(f)
+
+	return string(p2.buf)
+}
+
+// sequenceNumberPadding returns the number of spaces to print before each
+// element of a sequence of numbers printed on multiple lines to align them, or
+// nil if they are not to be aligned.
+func (p *Printer) sequenceNumberPadding(v reflect.Value, n int) []int {
+	if !p.alignNumbers || p.inline || !numericKind(v.Type().Elem().Kind()) {
+		return nil
+	}
+
+	var column numberColumn
+	values := make([]string, n)
+
+	for i := range n {
+		values[i] = p.plainString(v.Index(i))
+		if !p.decimalNumber(values[i]) {
+			return nil
+		}
+
+		column.add(values[i], true)
+	}
+
+	padding := make([]int, n)
+	for i, s := range values {
+		padding[i], _ = column.padding(s, true)
+	}
+
+	return padding
+}
+
+// sequenceRowAlignment returns the alignment of the fields of the elements of
+// a sequence of structures printed on multiple lines, or nil if they are not
+// to be aligned.
+func (p *Printer) sequenceRowAlignment(v reflect.Value, n int) *rowAlignment {
+	if !p.alignNumbers || p.inline {
+		return nil
+	}
+
+	structType := v.Type().Elem()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	a := rowAlignment{
+		structType: structType,
+		columns:    make(map[string]*numberColumn),
+	}
+
+	for i := range n {
+		ev := v.Index(i)
+		if ev.Kind() == reflect.Pointer {
+			if ev.IsNil() {
+				continue
+			}
+
+			ev = ev.Elem()
+		}
+
+		for _, f := range p.structFields(accessibleValue(ev)) {
+			if !scalarField(f) {
+				continue
+			}
+
+			column := a.columns[f.Name]
+			if column == nil {
+				column = &numberColumn{}
+				a.columns[f.Name] = column
+			}
+
+			column.add(p.numericFieldString(f))
+		}
+	}
+
+	if len(a.columns) == 0 {
+		return nil
+	}
+
+	return &a
+}
+
+// structNumberColumn returns the column used to align the numeric fields of
+// a structure printed on multiple lines and the width of the longest label,
+// used to align the values of all fields, or nil if they are not to be
+// aligned.
+func (p *Printer) structNumberColumn(fields []structField) (*numberColumn, int) {
+	if !p.alignNumbers || p.inline {
+		return nil, 0
+	}
+
+	var column numberColumn
+	var labelWidth, nbNumericFields int
+
+	for _, f := range fields {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(f.label()))
+
+		if s, numeric := p.numericFieldString(f); numeric {
+			column.add(s, true)
+			nbNumericFields++
+		}
+	}
+
+	if nbNumericFields < 2 {
+		return nil, 0
+	}
+
+	return &column, labelWidth
+}
+
+func (p *Printer) printPadding(n int) {
+	if n > 0 {
+		p.printString(strings.Repeat(" ", n))
+	}
+}
+
+// column returns the column of a field, or nil if the field is not aligned.
+func (a *rowAlignment) column(f structField) *numberColumn {
+	if a == nil || !scalarField(f) {
+		return nil
+	}
+
+	return a.columns[f.Name]
+}
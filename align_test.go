@@ -0,0 +1,69 @@
+package pp
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testAlignedValue struct {
+	Name  string
+	Price float64
+	Qty   int
+	D     time.Duration
+	Size  int `pp:"bytes"`
+	Flags int `pp:"hex"`
+	Ok    bool
+}
+
+func TestAlignNumbersMixedFields(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithAlignNumbers(true))
+
+	value := testAlignedValue{
+		Name:  "a",
+		Price: 1.5,
+		Qty:   10000,
+		D:     time.Hour,
+		Size:  1507328,
+		Flags: 255,
+		Ok:    true,
+	}
+
+	expected := `pp.testAlignedValue({
+  Name:  "a",
+  Price:     1.5,
+  Qty:   10000,
+  D:     time.Duration(1h0m0s),
+  Size:  1.44 MiB (1_507_328),
+  Flags: 0xff,
+  Ok:    true,
+})`
+
+	if s := p.String(value); s != expected {
+		t.Errorf("got:\n%s\nexpected:\n%s", s, expected)
+	}
+}
+
+func TestAlignNumbersSequenceRows(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithAlignNumbers(true))
+
+	value := []testAlignedValue{
+		{Name: "a", Price: 1.5, Qty: 10000, D: time.Hour},
+		{Name: "bcd", Price: 12.25, Qty: 3, D: time.Second},
+	}
+
+	s := p.String(value)
+
+	for _, line := range []string{
+		`    Price:     1.5,`,
+		`    Price: 12.25,`,
+		`    Qty:   10000,`,
+		`    Qty:    3,`,
+		`    D:     time.Duration(1s),`,
+	} {
+		if !slices.Contains(strings.Split(s, "\n"), line) {
+			t.Errorf("output does not contain line %q:\n%s", line, s)
+		}
+	}
+}
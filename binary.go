@@ -0,0 +1,59 @@
+package pp
+
+import (
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BinaryString is a heuristic returning whether a string contains binary data
+// rather than text. It can be used with (*Printer).SetBinaryStringFunc so
+// that binary strings are printed as hexadecimal dumps.
+//
+// A string is considered binary if more than 10% of its characters are either
+// invalid UTF-8 sequences or control characters other than whitespace, or if
+// it is long enough and its byte entropy is close to the one of random data.
+func BinaryString(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	var nbChars, nbBinaryChars int
+	for i := 0; i < len(s); {
+		c, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		nbChars++
+
+		if c == utf8.RuneError && size == 1 {
+			nbBinaryChars++
+		} else if unicode.IsControl(c) && !unicode.IsSpace(c) {
+			nbBinaryChars++
+		}
+	}
+
+	if nbBinaryChars > 0 && nbBinaryChars*10 >= nbChars {
+		return true
+	}
+
+	return len(s) >= 64 && byteEntropy(s) > 7.5
+}
+
+// byteEntropy returns the Shannon entropy of the bytes of a string in bits per
+// byte, between 0 and 8.
+func byteEntropy(s string) float64 {
+	var counts [256]int
+	for i := range len(s) {
+		counts[s[i]]++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(len(s))
+			entropy -= p * math.Log2(p)
+		}
+	}
+
+	return entropy
+}
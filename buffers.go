@@ -0,0 +1,49 @@
+package pp
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// Buffers are reused between calls so that printing values repeatedly, e.g. in
+// a loop, does not allocate new buffers each time. Very large buffers are not
+// kept around to avoid holding on to memory after printing a large value.
+
+const maxPooledBufferSize = 64 * 1024
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 1024)
+		return &buf
+	},
+}
+
+var writerPool = sync.Pool{
+	New: func() any {
+		return bufio.NewWriter(nil)
+	},
+}
+
+func getBuffer() []byte {
+	return (*bufferPool.Get().(*[]byte))[:0]
+}
+
+func putBuffer(buf []byte) {
+	if cap(buf) == 0 || cap(buf) > maxPooledBufferSize {
+		return
+	}
+
+	bufferPool.Put(&buf)
+}
+
+func getWriter(w io.Writer) *bufio.Writer {
+	bw := writerPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	return bw
+}
+
+func putWriter(bw *bufio.Writer) {
+	bw.Reset(nil)
+	writerPool.Put(bw)
+}
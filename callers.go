@@ -0,0 +1,63 @@
+package pp
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// callerLocation returns the location of the first function of the call stack
+// which is not part of the pp package, i.e. the place where the printing
+// function was called.
+func callerLocation(withFunction bool) string {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+
+		if !ppFunction(frame.Function) {
+			s := filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+			if withFunction && frame.Function != "" {
+				s += " " + shortFunctionName(frame.Function)
+			}
+
+			return s
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+func ppFunction(name string) bool {
+	return strings.HasPrefix(name, "go.n16f.net/pp.")
+}
+
+// shortFunctionName removes the import path from a function name, keeping
+// the package name.
+func shortFunctionName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+// goroutineID returns the identifier of the current goroutine. The runtime
+// does not expose it, so we extract it from the first line of the stack
+// trace, e.g. "goroutine 42 [running]:".
+func goroutineID() string {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	s, _ := strings.CutPrefix(string(buf[:n]), "goroutine ")
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		return s[:i]
+	}
+
+	return "?"
+}
@@ -0,0 +1,121 @@
+package pp
+
+import (
+	"unicode/utf8"
+)
+
+type Theme struct {
+	Type       string
+	FieldName  string
+	String     string
+	Number     string
+	Keyword    string
+	Annotation string
+	Added      string
+	Removed    string
+	Label      string
+}
+
+var DefaultTheme = Theme{
+	Type:       "34",
+	FieldName:  "33",
+	String:     "32",
+	Number:     "36",
+	Keyword:    "35",
+	Annotation: "2",
+	Added:      "32",
+	Removed:    "31",
+	Label:      "1",
+}
+
+// DefaultLevelColors is a palette which can be used with SetLevelColors.
+var DefaultLevelColors = []string{"31", "33", "32", "36", "34", "35"}
+
+// levelColor returns the color of delimiters and field names at the current
+// nesting level if level colors are enabled, or an empty string.
+func (p *Printer) levelColor() string {
+	if len(p.levelColors) == 0 {
+		return ""
+	}
+
+	return p.levelColors[p.level%len(p.levelColors)]
+}
+
+func (p *Printer) printDelimiter(s string) {
+	p.printColoredString(p.levelColor(), s)
+}
+
+func (p *Printer) fieldNameColor() string {
+	if color := p.levelColor(); color != "" {
+		return color
+	}
+
+	return p.theme.FieldName
+}
+
+func (p *Printer) printColoredString(color, s string) {
+	if !p.colors || color == "" {
+		p.printString(s)
+		return
+	}
+
+	p.printColorStart(color)
+	p.printString(s)
+	p.printColorEnd()
+}
+
+func (p *Printer) printColoredBytes(color string, data []byte) {
+	if !p.colors || color == "" {
+		p.printBytes(data)
+		return
+	}
+
+	p.printColorStart(color)
+	p.printBytes(data)
+	p.printColorEnd()
+}
+
+// Escape sequences are written directly to the buffer so that they are not
+// counted in the width of inline values.
+
+func (p *Printer) printColorStart(color string) {
+	if p.overflow {
+		return
+	}
+
+	p.buf = append(p.buf, "\x1b["...)
+	p.buf = append(p.buf, color...)
+	p.buf = append(p.buf, 'm')
+}
+
+func (p *Printer) printColorEnd() {
+	if p.overflow {
+		return
+	}
+
+	p.buf = append(p.buf, "\x1b[0m"...)
+}
+
+// textWidth returns the number of characters in data, ignoring ANSI escape
+// sequences so that colored output is not considered longer than it actually
+// is when deciding whether to inline a value.
+func textWidth(data []byte) int {
+	n := 0
+
+	for i := 0; i < len(data); {
+		if data[i] == 0x1b && i+1 < len(data) && data[i+1] == '[' {
+			i += 2
+			for i < len(data) && (data[i] < 0x40 || data[i] > 0x7e) {
+				i++
+			}
+			i++
+			continue
+		}
+
+		_, size := utf8.DecodeRune(data[i:])
+		i += size
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,71 @@
+package pp
+
+import (
+	"container/list"
+	"container/ring"
+	"reflect"
+)
+
+var (
+	listType = reflect.TypeFor[list.List]()
+	ringType = reflect.TypeFor[ring.Ring]()
+)
+
+// collectionContent returns the content of collection types whose internal
+// structure is not useful to print as a map or a slice.
+func collectionContent(v reflect.Value) (reflect.Value, bool) {
+	switch v.Type() {
+	case syncMapType:
+		return syncMapContent(v)
+	case listType:
+		return listContent(v)
+	case ringType:
+		return ringContent(v)
+	}
+
+	return reflect.Value{}, false
+}
+
+func listContent(v reflect.Value) (reflect.Value, bool) {
+	l, ok := valueAs[*list.List](v)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	// Stop after the number of elements of the list in case it was modified
+	// incorrectly and contains a cycle.
+	values := make([]reflect.Value, 0, l.Len())
+	for e := l.Front(); e != nil && len(values) < l.Len(); e = e.Next() {
+		values = append(values, reflect.ValueOf(e.Value))
+	}
+
+	return makeSlice(values), true
+}
+
+func ringContent(v reflect.Value) (reflect.Value, bool) {
+	r, ok := valueAs[*ring.Ring](v)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	var values []reflect.Value
+	r.Do(func(value any) {
+		values = append(values, reflect.ValueOf(value))
+	})
+
+	return makeSlice(values), true
+}
+
+func makeSlice(values []reflect.Value) reflect.Value {
+	elementType := commonType(values)
+
+	s := reflect.MakeSlice(reflect.SliceOf(elementType), len(values),
+		len(values))
+	for i, value := range values {
+		if value.IsValid() {
+			s.Index(i).Set(value)
+		}
+	}
+
+	return s
+}
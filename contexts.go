@@ -0,0 +1,84 @@
+package pp
+
+import (
+	"context"
+	"io"
+)
+
+// renderCancellation is the value of the panic used to stop rendering a value
+// when the context of the printer is done. Printers never recover it while
+// printing values so that it reaches the render function.
+type renderCancellation struct {
+	err error
+}
+
+// renderAborted returns whether a panic value must stop rendering instead of
+// being printed as an error.
+func renderAborted(value any) bool {
+	switch value.(type) {
+	case renderCancellation, yieldPanic:
+		return true
+	}
+
+	return false
+}
+
+func PrintContext(ctx context.Context, w io.Writer, value any, label ...any) error {
+	return DefaultPrinter.PrintContext(ctx, w, value, label...)
+}
+
+// PrintContext prints a value as PrintTo does, but checks the context before
+// each value it contains. If the context is done, printing stops, a marker
+// indicating why is printed at the end of the truncated output, and the cause
+// of the context cancellation is returned. If the context is done after the
+// value was entirely printed, no error is returned.
+func (p *Printer) PrintContext(ctx context.Context, w io.Writer, value any, label ...any) error {
+	label, opts := splitOptions(label)
+	p2 := p.snapshot(opts)
+	p2.ctx = ctx
+
+	p2.reset(value)
+	defer p2.releaseBuffer()
+
+	if w == nil {
+		w = p2.defaultOutput
+	}
+
+	if err := p.renderOutput(p2, w, value, label...); err != nil {
+		return err
+	}
+
+	return p2.cancellationCause
+}
+
+func (p *Printer) contextDone() bool {
+	return p.ctx != nil && p.ctx.Err() != nil
+}
+
+func (p *Printer) checkContext() {
+	if p.contextDone() {
+		panic(renderCancellation{err: context.Cause(p.ctx)})
+	}
+}
+
+// printDocumentContext prints a document, printing a cancellation marker
+// instead of the rest of the document if the context of the printer is done
+// while printing it.
+func (p *Printer) printDocumentContext(value any) {
+	defer func() {
+		if value := recover(); value != nil {
+			cancellation, ok := value.(renderCancellation)
+			if !ok {
+				panic(value)
+			}
+
+			p.cancellationCause = cancellation.err
+
+			p.inline = false
+			p.printColoredString(p.theme.Annotation,
+				"… <"+cancellation.err.Error()+">")
+		}
+	}()
+
+	p.printDocument(value)
+}
@@ -0,0 +1,64 @@
+package pp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testCancelWriter cancels a context the first time data are written to it.
+type testCancelWriter struct {
+	bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (w *testCancelWriter) Write(data []byte) (int, error) {
+	w.cancel()
+	return w.Buffer.Write(data)
+}
+
+func TestPrintContext(t *testing.T) {
+	p := NewPrinter(WithColors(false))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	if err := p.PrintContext(ctx, &buf, []int{1, 2, 3}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, expected %v", err, context.Canceled)
+	}
+
+	if s := buf.String(); !strings.Contains(s, "<context canceled>") {
+		t.Errorf("missing cancellation marker in %q", s)
+	}
+
+	// The context is canceled once the value has been entirely printed
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	w := testCancelWriter{cancel: cancel}
+	if err := p.PrintContext(ctx, &w, []int{1, 2, 3}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if s := w.String(); s != "[]int([1, 2, 3])\n" {
+		t.Errorf("got %q", s)
+	}
+}
+
+func TestPrintContextDeduplication(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithDeduplicateOutput(true))
+
+	var buf bytes.Buffer
+	for range 3 {
+		if err := p.PrintContext(context.Background(), &buf, 42); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	if s := buf.String(); s != "42\n" {
+		t.Errorf("got %q, expected %q", s, "42\n")
+	}
+}
@@ -0,0 +1,112 @@
+package pp
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io"
+	"strconv"
+)
+
+// Output deduplication state. It is part of the printer and not of its
+// configuration, so that it is shared by all the values printed, and is
+// protected by the mutex of the printer. Output is never written while the
+// printer is locked, so that writers can themselves use the printer.
+type outputRepeats struct {
+	hash   uint64
+	w      io.Writer
+	count  int
+	active bool
+}
+
+func FlushRepeats() error {
+	return DefaultPrinter.FlushRepeats()
+}
+
+// FlushRepeats prints the number of times the last value was repeated, if
+// output deduplication is enabled and the last value was printed more than
+// once. It is called automatically when a different value is printed. The
+// next value printed is always printed in full.
+func (p *Printer) FlushRepeats() error {
+	p.mu.Lock()
+	p.repeats.active = false
+	w, marker := p.takeRepeats()
+	p.mu.Unlock()
+
+	return writeRepeats(w, marker)
+}
+
+// takeRepeats resets the repeat counter and returns the line to write to
+// indicate the number of repeats if there were any. The printer must be
+// locked.
+func (p *Printer) takeRepeats() (io.Writer, string) {
+	r := &p.repeats
+
+	count := r.count
+	r.count = 0
+
+	if count == 0 {
+		return nil, ""
+	}
+
+	return r.w, p.linePrefix + "(repeated ×" + strconv.Itoa(count+1) + ")\n"
+}
+
+func writeRepeats(w io.Writer, marker string) error {
+	if marker == "" {
+		return nil
+	}
+
+	_, err := io.WriteString(w, marker)
+	return err
+}
+
+// renderOutput renders a value to a writer with p2, a snapshot of p,
+// deduplicating the output if p2 is configured to.
+func (p *Printer) renderOutput(p2 *Printer, w io.Writer, value any, label ...any) error {
+	r := &p.repeats
+
+	if !p2.deduplicateOutput {
+		p.mu.Lock()
+		r.active = false
+		repeatsWriter, marker := p.takeRepeats()
+		p.mu.Unlock()
+
+		if err := writeRepeats(repeatsWriter, marker); err != nil {
+			return err
+		}
+
+		return p2.render(w, w, value, label...)
+	}
+
+	var buf bytes.Buffer
+	if err := p2.render(w, &buf, value, label...); err != nil {
+		return err
+	}
+
+	hash := fnv.New64a()
+	hash.Write(buf.Bytes())
+	sum := hash.Sum64()
+
+	p.mu.Lock()
+
+	if r.active && sum == r.hash {
+		r.count++
+		p.mu.Unlock()
+		return nil
+	}
+
+	repeatsWriter, marker := p.takeRepeats()
+
+	r.hash = sum
+	r.w = w
+	r.active = true
+
+	p.mu.Unlock()
+
+	if err := writeRepeats(repeatsWriter, marker); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,376 @@
+package pp
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type diffNodeType int
+
+const (
+	diffNodeEqual diffNodeType = iota
+	diffNodeChanged
+	diffNodeAdded
+	diffNodeRemoved
+	diffNodeNested
+)
+
+type diffNode struct {
+	nodeType diffNodeType
+	prefix   string
+	a, b     reflect.Value
+
+	open, close string
+	children    []*diffNode
+}
+
+type diffPointerPair struct {
+	a, b uintptr
+}
+
+type differ struct {
+	p       *Printer
+	visited map[diffPointerPair]struct{}
+	depth   int
+}
+
+func Diff(a, b any) string {
+	return DefaultPrinter.Diff(a, b)
+}
+
+// Diff returns a representation of the differences between two values, or an
+// empty string if they are identical. Lines starting with "-" only exist in
+// the first value and lines starting with "+" only exist in the second one.
+func (p *Printer) Diff(a, b any) string {
+	p = p.snapshot(nil)
+
+	p.reset(nil)
+	defer p.releaseBuffer()
+
+	d := differ{
+		p:       p,
+		visited: make(map[diffPointerPair]struct{}),
+	}
+
+	root := d.diffValues("", reflectValue(a), reflectValue(b))
+	if root.nodeType == diffNodeEqual {
+		return ""
+	}
+
+	d.printNode(root, "")
+
+	return string(p.buf)
+}
+
+func DiffFromZero(value any, label ...any) error {
+	return DefaultPrinter.DiffFromZero(value, label...)
+}
+
+// DiffFromZero prints a value using the zero value of its type as baseline, so
+// that only the fields of structures which are set are printed. Zero fields of
+// structures contained in sequences and maps are omitted as well.
+func (p *Printer) DiffFromZero(value any, label ...any) error {
+	var baseline any
+
+	if t := reflect.TypeOf(value); t != nil {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		baseline = reflect.New(t).Interface()
+	}
+
+	label = append(slices.Clip(label),
+		WithBaseline(baseline), WithOmitZeroFields(true))
+
+	return p.Print(value, label...)
+}
+
+func (d *differ) diffValues(prefix string, a, b reflect.Value) *diffNode {
+	n := diffNode{prefix: prefix, a: a, b: b}
+
+	if d.depth >= maxRecursionDepth {
+		return d.diffLeaves(&n, a, b)
+	}
+
+	d.depth++
+	defer func() { d.depth-- }()
+
+	if d.p.skippedValue(a) || d.p.skippedValue(b) {
+		return d.diffLeaves(&n, a, b)
+	}
+
+	if a.Kind() != b.Kind() || (a.Kind() != 0 && a.Type() != b.Type()) {
+		n.nodeType = diffNodeChanged
+		return &n
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		d.diffStructs(&n, a, b)
+
+	case reflect.Array:
+		d.diffSequences(&n, a, b)
+
+	case reflect.Slice:
+		if a.IsNil() || b.IsNil() || !d.visit(a, b) {
+			return d.diffLeaves(&n, a, b)
+		}
+
+		d.diffSequences(&n, a, b)
+
+	case reflect.Map:
+		if a.IsNil() || b.IsNil() || !d.visit(a, b) {
+			return d.diffLeaves(&n, a, b)
+		}
+
+		d.diffMaps(&n, a, b)
+
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() || !d.visit(a, b) {
+			return d.diffLeaves(&n, a, b)
+		}
+
+		n2 := d.diffValues(prefix+"&", a.Elem(), b.Elem())
+		if n2.nodeType == diffNodeChanged {
+			n.nodeType = diffNodeChanged
+			return &n
+		}
+
+		return n2
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return d.diffLeaves(&n, a, b)
+		}
+
+		return d.diffValues(prefix, a.Elem(), b.Elem())
+
+	default:
+		return d.diffLeaves(&n, a, b)
+	}
+
+	n.nodeType = diffNodeEqual
+	for _, child := range n.children {
+		if child.nodeType != diffNodeEqual {
+			n.nodeType = diffNodeNested
+			break
+		}
+	}
+
+	return &n
+}
+
+func (d *differ) visit(a, b reflect.Value) bool {
+	pair := diffPointerPair{a: a.Pointer(), b: b.Pointer()}
+
+	if _, found := d.visited[pair]; found {
+		return false
+	}
+
+	d.visited[pair] = struct{}{}
+	return true
+}
+
+func (d *differ) diffLeaves(n *diffNode, a, b reflect.Value) *diffNode {
+	if d.renderInline(a) == d.renderInline(b) {
+		n.nodeType = diffNodeEqual
+	} else {
+		n.nodeType = diffNodeChanged
+	}
+
+	return n
+}
+
+func (d *differ) diffStructs(n *diffNode, a, b reflect.Value) {
+	n.open, n.close = d.delimiters(a, "{", "}")
+
+	for _, f := range d.p.structFields(a) {
+		if f.redaction != "" {
+			continue
+		}
+
+		prefix := f.label() + ": "
+		n.children = append(n.children,
+			d.diffValues(prefix, f.value, b.FieldByIndex(f.Index)))
+	}
+}
+
+func (d *differ) diffSequences(n *diffNode, a, b reflect.Value) {
+	n.open, n.close = d.delimiters(a, "[", "]")
+
+	for i := range max(a.Len(), b.Len()) {
+		switch {
+		case i >= a.Len():
+			n.children = append(n.children,
+				&diffNode{nodeType: diffNodeAdded, b: b.Index(i)})
+
+		case i >= b.Len():
+			n.children = append(n.children,
+				&diffNode{nodeType: diffNodeRemoved, a: a.Index(i)})
+
+		default:
+			n.children = append(n.children,
+				d.diffValues("", a.Index(i), b.Index(i)))
+		}
+	}
+}
+
+func (d *differ) diffMaps(n *diffNode, a, b reflect.Value) {
+	n.open, n.close = d.delimiters(a, "{", "}")
+
+	keys := a.MapKeys()
+	for _, key := range b.MapKeys() {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.SortFunc(keys, d.p.compareMapKeys)
+
+	for _, key := range keys {
+		prefix := d.renderInline(key) + ": "
+
+		av := a.MapIndex(key)
+		bv := b.MapIndex(key)
+
+		switch {
+		case !av.IsValid():
+			n.children = append(n.children,
+				&diffNode{nodeType: diffNodeAdded, prefix: prefix, b: bv})
+
+		case !bv.IsValid():
+			n.children = append(n.children,
+				&diffNode{nodeType: diffNodeRemoved, prefix: prefix, a: av})
+
+		default:
+			n.children = append(n.children, d.diffValues(prefix, av, bv))
+		}
+	}
+}
+
+func (d *differ) delimiters(v reflect.Value, open, close string) (string, string) {
+	if d.p.printTypeForValue(v) {
+		typeString := d.p.valueTypeString(v)
+		return typeString + "(" + open, close + ")"
+	}
+
+	return open, close
+}
+
+func (d *differ) printNode(n *diffNode, suffix string) {
+	switch n.nodeType {
+	case diffNodeEqual:
+		d.printLines(' ', n.prefix, d.renderEqual(n.a), suffix)
+
+	case diffNodeChanged:
+		d.printLines('-', n.prefix, d.render(n.a), suffix)
+		d.printLines('+', n.prefix, d.render(n.b), suffix)
+
+	case diffNodeAdded:
+		d.printLines('+', n.prefix, d.render(n.b), suffix)
+
+	case diffNodeRemoved:
+		d.printLines('-', n.prefix, d.render(n.a), suffix)
+
+	case diffNodeNested:
+		d.printLines(' ', n.prefix, n.open, "")
+
+		d.p.level++
+		for _, child := range n.children {
+			d.printNode(child, ",")
+		}
+		d.p.level--
+
+		d.printLines(' ', "", n.close, suffix)
+	}
+}
+
+func (d *differ) printLines(marker byte, prefix, s, suffix string) {
+	var color string
+	switch marker {
+	case '-':
+		color = d.p.theme.Removed
+	case '+':
+		color = d.p.theme.Added
+	}
+
+	lines := strings.Split(s, "\n")
+
+	for i, line := range lines {
+		var buf strings.Builder
+
+		buf.WriteByte(marker)
+		buf.WriteByte(' ')
+
+		if i == 0 {
+			buf.WriteString(strings.Repeat(d.p.indent, d.p.level))
+			buf.WriteString(prefix)
+		}
+
+		buf.WriteString(line)
+
+		if i == len(lines)-1 {
+			buf.WriteString(suffix)
+		}
+
+		d.p.printString(d.p.linePrefix)
+		d.p.printColoredString(color, buf.String())
+		d.p.printNewline()
+	}
+}
+
+// render returns the representation of a value as it would be printed at the
+// current level. Lines after the first one include their indentation.
+func (d *differ) render(v reflect.Value) string {
+	p2 := d.p.clone()
+	p2.buf = nil
+	p2.linePrefix = ""
+	p2.colors = false
+	p2.initPointers(v)
+
+	p2.printValue(v)
+
+	return string(p2.buf)
+}
+
+func (d *differ) renderInline(v reflect.Value) string {
+	p2 := d.p.clone()
+	p2.buf = nil
+	p2.colors = false
+	p2.inline = true
+	p2.initPointers(v)
+
+	p2.printValue(v)
+
+	return string(p2.buf)
+}
+
+// renderEqual returns the representation of a value which is identical in
+// both values. Since identical parts are only useful as context, values which
+// cannot be printed on a single line are elided.
+func (d *differ) renderEqual(v reflect.Value) string {
+	s := d.renderInline(v)
+	if textWidth([]byte(s)) <= d.p.currentMaxInlineColumn() {
+		return s
+	}
+
+	for _, depth := range []int{d.p.level + 1, d.p.level} {
+		p2 := d.p.clone()
+		p2.maxDepth = depth
+		p2.buf = nil
+		p2.colors = false
+		p2.inline = true
+		p2.initPointers(v)
+
+		p2.printValue(v)
+
+		s = string(p2.buf)
+		if textWidth(p2.buf) <= d.p.currentMaxInlineColumn() {
+			break
+		}
+	}
+
+	return s
+}
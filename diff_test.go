@@ -0,0 +1,24 @@
+package pp
+
+import "testing"
+
+func TestDiffNil(t *testing.T) {
+	p := NewPrinter(WithColors(false))
+
+	tests := []struct {
+		a, b any
+		diff string
+	}{
+		{nil, nil, ""},
+		{nil, 42, "- nil\n+ 42\n"},
+		{"foo", nil, "- \"foo\"\n+ nil\n"},
+		{nil, []int{1}, "- nil\n+ []int([1])\n"},
+	}
+
+	for _, test := range tests {
+		if diff := p.Diff(test.a, test.b); diff != test.diff {
+			t.Errorf("Diff(%#v, %#v): got %q, expected %q",
+				test.a, test.b, diff, test.diff)
+		}
+	}
+}
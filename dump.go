@@ -0,0 +1,52 @@
+package pp
+
+import (
+	"io"
+	"strings"
+)
+
+// The Sprint, Sdump and Fdump functions have the same signature as their
+// equivalent in the go-spew library, making it easy to switch from one to the
+// other.
+
+func Sprint(values ...any) string {
+	return DefaultPrinter.Sprint(values...)
+}
+
+func Sdump(values ...any) string {
+	return DefaultPrinter.Sdump(values...)
+}
+
+func Fdump(w io.Writer, values ...any) error {
+	return DefaultPrinter.Fdump(w, values...)
+}
+
+// Sprint returns the representation of values on a single line, separated by
+// spaces.
+func (p *Printer) Sprint(values ...any) string {
+	values, opts := splitOptions(values)
+
+	var buf strings.Builder
+
+	for i, value := range values {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+
+		p.renderInline(&buf, value, opts...)
+	}
+
+	return buf.String()
+}
+
+// Sdump returns the representation of values as printed by Println.
+func (p *Printer) Sdump(values ...any) string {
+	var buf strings.Builder
+	p.PrintlnTo(&buf, values...)
+	return buf.String()
+}
+
+// Fdump writes the representation of values as printed by Println.
+func (p *Printer) Fdump(w io.Writer, values ...any) error {
+	return p.PrintlnTo(w, values...)
+}
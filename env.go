@@ -0,0 +1,48 @@
+package pp
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The default printer is configured using environment variables when the
+// package is initialized. Invalid values are ignored, and settings can still
+// be modified by calling the setters of the default printer.
+
+func init() {
+	loadEnvConfig(&DefaultPrinter)
+}
+
+func loadEnvConfig(p *Printer) {
+	if s := os.Getenv("PP_MAX_INLINE_COLUMN"); s != "" {
+		if column, err := strconv.Atoi(s); err == nil && column > 0 {
+			p.SetMaxInlineColumn(column)
+		}
+	}
+
+	if s := os.Getenv("PP_INDENT"); s != "" {
+		// Accept a number of spaces in addition to a literal string since
+		// tabulations are hard to pass in some environments.
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			p.SetIndent(strings.Repeat(" ", n))
+		} else {
+			p.SetIndent(s)
+		}
+	}
+
+	if s := os.Getenv("PP_COLORS"); s != "" {
+		if s == "auto" {
+			p.SetAutoDetect(true)
+		} else if colors, err := strconv.ParseBool(s); err == nil {
+			p.SetColors(colors)
+			p.SetAutoDetect(false)
+		}
+	}
+
+	if s := os.Getenv("PP_HIDE_PRIVATE"); s != "" {
+		if hide, err := strconv.ParseBool(s); err == nil {
+			p.SetHidePrivateFields(hide)
+		}
+	}
+}
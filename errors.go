@@ -0,0 +1,104 @@
+package pp
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// errorValue returns the error a value implements, if any. Nil pointers are
+// ignored since calling Error on them usually panics.
+func (p *Printer) errorValue(v reflect.Value) (error, bool) {
+	if p.rawErrors {
+		return nil, false
+	}
+
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case 0, reflect.Interface:
+		return nil, false
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, false
+		}
+	}
+
+	if !v.Type().Implements(errorType) {
+		return nil, false
+	}
+
+	value, ok := valueInterface(v)
+	if !ok {
+		return nil, false
+	}
+
+	return value.(error), true
+}
+
+func unwrapError(err error) []error {
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		if err2 := err.Unwrap(); err2 != nil {
+			return []error{err2}
+		}
+	case interface{ Unwrap() []error }:
+		return err.Unwrap()
+	}
+
+	return nil
+}
+
+// printErrorValue prints the type and message of an error. Errors wrapping
+// other errors are followed by the tree of wrapped errors, unless the value
+// is printed inline.
+func (p *Printer) printErrorValue(err error) {
+	p.printErrorMessage(err)
+
+	if !p.inline {
+		p.level++
+		p.printWrappedErrors(err, "")
+		p.level--
+	}
+}
+
+func (p *Printer) printErrorMessage(err error) {
+	if p.printTypes != PrintTypesNever {
+		p.printColoredString(p.theme.Type,
+			p.valueTypeString(reflect.ValueOf(err)))
+		p.printByte('(')
+	}
+
+	p.printColoredString(p.theme.String, strconv.Quote(err.Error()))
+
+	if p.printTypes != PrintTypesNever {
+		p.printByte(')')
+	}
+}
+
+func (p *Printer) printWrappedErrors(err error, prefix string) {
+	errs := unwrapError(err)
+
+	for i, err2 := range errs {
+		last := i == len(errs)-1
+
+		p.printNewline()
+		p.printLineStart()
+		p.printString(prefix)
+
+		if last {
+			p.printColoredString(p.theme.Annotation, "└─ ")
+		} else {
+			p.printColoredString(p.theme.Annotation, "├─ ")
+		}
+
+		p.printErrorMessage(err2)
+
+		if last {
+			p.printWrappedErrors(err2, prefix+"   ")
+		} else {
+			p.printWrappedErrors(err2, prefix+"│  ")
+		}
+	}
+}
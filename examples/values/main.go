@@ -22,6 +22,12 @@ type Bar struct {
 	Foo *Foo
 }
 
+type Node struct {
+	Name     string
+	Children [4]*Node
+	Value    any
+}
+
 type Point struct {
 	X int
 	Y int
@@ -79,6 +85,17 @@ func main() {
 
 	pp.Print(&foo1)
 
+	node1 := Node{Name: "node1"}
+	node2 := Node{Name: "node2", Value: &node1}
+	node1.Children[0] = &node2
+	node1.Children[1] = &node1
+
+	var self any
+	self = &self
+
+	pp.Print([2]*Node{&node1, &node2}, "cyclic array")
+	pp.Print(self, "cyclic interface")
+
 	// Inline content
 	printTitle("INLINE CONTENT")
 
@@ -0,0 +1,229 @@
+package pp
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type structField struct {
+	reflect.StructField
+
+	value reflect.Value
+	tag   fieldTag
+
+	// The string printed instead of the value of a redacted field
+	redaction string
+
+	// The corresponding field of the baseline value, if there is one
+	baseline reflect.Value
+}
+
+// fieldTag contains the options of the "pp" struct tag, a comma separated
+// list of options:
+//
+//   - "-": do not print the field;
+//   - "redact": print "***" instead of the value of the field;
+//   - "name=<name>": use <name> as label for the field;
+//   - "bin", "oct", "dec", "hex": the base used to print integers;
+//   - "bytes": print integers as a size in bytes, e.g. "1.44 MiB (1_507_328)".
+type fieldTag struct {
+	skip        bool
+	redact      bool
+	name        string
+	integerBase int
+	byteSize    bool
+}
+
+func parseFieldTag(tag reflect.StructTag) fieldTag {
+	var ft fieldTag
+
+	s, found := tag.Lookup("pp")
+	if !found {
+		return ft
+	}
+
+	for _, option := range strings.Split(s, ",") {
+		switch option = strings.TrimSpace(option); option {
+		case "-":
+			ft.skip = true
+		case "redact":
+			ft.redact = true
+		case "bin":
+			ft.integerBase = 2
+		case "oct":
+			ft.integerBase = 8
+		case "dec":
+			ft.integerBase = 10
+		case "hex":
+			ft.integerBase = 16
+		case "bytes":
+			ft.byteSize = true
+		default:
+			if name, found := strings.CutPrefix(option, "name="); found {
+				ft.name = name
+			}
+		}
+	}
+
+	return ft
+}
+
+func (f *structField) label() string {
+	if f.tag.name != "" {
+		return f.tag.name
+	}
+
+	return f.Name
+}
+
+// fieldTagName returns the name of a field in the struct tag selected by the
+// field name source, or an empty string if there is none.
+func (p *Printer) fieldTagName(tag reflect.StructTag) string {
+	var key string
+
+	switch p.fieldNameSource {
+	case FieldNameSourceJSONTag:
+		key = "json"
+	case FieldNameSourceYAMLTag:
+		key = "yaml"
+	default:
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// structFields returns the fields of a structure which are to be printed.
+func (p *Printer) structFields(v reflect.Value) []structField {
+	vt := v.Type()
+
+	hideProtobufInternals := !p.showProtobufInternals &&
+		isProtobufMessageType(vt)
+
+	fields := make([]structField, 0, vt.NumField())
+
+	for i := range vt.NumField() {
+		f := structField{
+			StructField: vt.Field(i),
+			value:       v.Field(i),
+		}
+
+		if !f.IsExported() && p.hidePrivateFields {
+			continue
+		}
+
+		if hideProtobufInternals && protobufInternalField(f.Name) {
+			continue
+		}
+
+		f.tag = parseFieldTag(f.Tag)
+		if f.tag.skip {
+			continue
+		}
+
+		if f.tag.name == "" {
+			f.tag.name = p.fieldTagName(f.Tag)
+		}
+
+		if p.fieldFilter != nil && !p.fieldFilter(f.StructField, f.value) {
+			continue
+		}
+
+		if !unsafeAccess && !f.IsExported() {
+			f.redaction = "<unexported>"
+		} else if f.tag.redact {
+			f.redaction = "***"
+		} else if f.Type.Kind() == reflect.String && p.redactedFieldName(f.Name) {
+			f.redaction = "[REDACTED]"
+		}
+
+		fields = append(fields, f)
+	}
+
+	if p.fieldCompare != nil {
+		slices.SortStableFunc(fields, func(f1, f2 structField) int {
+			return p.fieldCompare(f1.StructField, f2.StructField)
+		})
+	} else if p.fieldOrder == FieldOrderAlphabetical {
+		slices.SortStableFunc(fields, func(f1, f2 structField) int {
+			return strings.Compare(f1.label(), f2.label())
+		})
+	}
+
+	return fields
+}
+
+func (p *Printer) redactedFieldName(name string) bool {
+	for _, re := range p.redactPatterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nonZeroFields removes fields whose value is the zero value of their type if
+// the printer is configured to do so, or fields equal to the corresponding
+// field of the baseline value if there is one, returning the remaining fields
+// and the number of fields removed.
+func (p *Printer) nonZeroFields(v reflect.Value, fields []structField) ([]structField, int) {
+	baseline := p.structBaseline(v)
+
+	if !p.omitZeroFields && !baseline.IsValid() {
+		return fields, 0
+	}
+
+	fields2 := make([]structField, 0, len(fields))
+	for _, f := range fields {
+		if baseline.IsValid() {
+			// Fields promoted through a nil embedded pointer have no
+			// corresponding value.
+			bf, err := baseline.FieldByIndexErr(f.Index)
+			if err == nil {
+				f.baseline = accessibleValue(bf)
+			}
+
+			if err != nil || !equalValues(f.value, f.baseline) {
+				fields2 = append(fields2, f)
+			}
+		} else if !f.value.IsZero() {
+			fields2 = append(fields2, f)
+		}
+	}
+
+	return fields2, len(fields) - len(fields2)
+}
+
+// structBaseline returns the baseline value corresponding to a structure
+// being printed, or an invalid value if there is none.
+func (p *Printer) structBaseline(v reflect.Value) reflect.Value {
+	baseline := p.baselineValue
+
+	for baseline.Kind() == reflect.Pointer || baseline.Kind() == reflect.Interface {
+		if baseline.IsNil() {
+			return reflect.Value{}
+		}
+
+		baseline = baseline.Elem()
+	}
+
+	if !baseline.IsValid() || baseline.Type() != v.Type() {
+		return reflect.Value{}
+	}
+
+	return accessibleValue(baseline)
+}
+
+func equalValues(v1, v2 reflect.Value) bool {
+	i1, ok1 := valueInterface(accessibleValue(v1))
+	i2, ok2 := valueInterface(accessibleValue(v2))
+
+	return ok1 && ok2 && reflect.DeepEqual(i1, i2)
+}
@@ -0,0 +1,155 @@
+package pp
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// FileSink is a writer appending data to a file which is rotated when it
+// reaches a maximum size: the current file is renamed with a ".1" suffix,
+// the previous ".1" file becomes ".2", and so on, files beyond the maximum
+// number of files being deleted. File sinks can be used concurrently, e.g. as
+// output of several printers.
+//
+// Rotation happens between calls to Write. Printers write the representation
+// of large values with several calls, so the output of printers sharing a sink
+// can be interleaved, and a value can be split across two files.
+type FileSink struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file   *os.File
+	size   int64
+	closed bool
+
+	mu sync.Mutex
+}
+
+// NewFileSink opens a file sink. A maximum size of zero disables rotation,
+// and maxFiles is the number of rotated files kept in addition to the
+// current one.
+func NewFileSink(path string, maxSize int64, maxFiles int) (*FileSink, error) {
+	s := FileSink{
+		path:     path,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open %q: %w", s.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("cannot stat %q: %w", s.path, err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+func (s *FileSink) Write(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, os.ErrClosed
+	}
+
+	// The file is not open if it could not be reopened after a rotation
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+
+	return n, err
+}
+
+// rotate closes the current file, renames it and opens a new file. If the
+// rotation fails, the current path is reopened so that the sink is still
+// usable.
+func (s *FileSink) rotate() error {
+	err := s.file.Close()
+	s.file = nil
+
+	if err != nil {
+		err = fmt.Errorf("cannot close %q: %w", s.path, err)
+	} else {
+		err = s.renameFiles()
+	}
+
+	if err2 := s.open(); err == nil {
+		err = err2
+	}
+
+	return err
+}
+
+func (s *FileSink) renameFiles() error {
+	if s.maxFiles > 0 {
+		os.Remove(s.rotatedPath(s.maxFiles))
+
+		for i := s.maxFiles - 1; i >= 1; i-- {
+			err := os.Rename(s.rotatedPath(i), s.rotatedPath(i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("cannot rename %q: %w", s.rotatedPath(i), err)
+			}
+		}
+
+		if err := os.Rename(s.path, s.rotatedPath(1)); err != nil {
+			return fmt.Errorf("cannot rename %q: %w", s.path, err)
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return fmt.Errorf("cannot delete %q: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *FileSink) rotatedPath(i int) string {
+	return s.path + "." + strconv.Itoa(i)
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
@@ -0,0 +1,54 @@
+package pp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkRotationFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.log")
+
+	s, err := NewFileSink(path, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// A non-empty directory cannot be replaced by the rotated file
+	rotatedPath := filepath.Join(dir, "out.log.1")
+	if err := os.MkdirAll(filepath.Join(rotatedPath, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Write([]byte("abcdef\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Write([]byte("ghijkl\n")); err == nil {
+		t.Fatal("rotation did not fail")
+	}
+
+	if err := os.RemoveAll(rotatedPath); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Write([]byte("mnopqr\n")); err != nil {
+		t.Fatalf("sink not usable after rotation failure: %v", err)
+	}
+
+	for path, content := range map[string]string{
+		path:        "mnopqr\n",
+		rotatedPath: "abcdef\n",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != content {
+			t.Errorf("%s contains %q, expected %q", path, data, content)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package pp
+
+import (
+	"strconv"
+)
+
+// The flat format prints one line per leaf value, made of the path of the
+// value, using the syntax accepted by Get, and its representation, e.g.
+// "Server.Listeners[0].Port = 8080". Empty sequences, maps and structures
+// are leaves.
+//
+// The logfmt format prints the same leaves as key=value pairs on a single
+// line, all path segments being separated by dots.
+
+type flatPrinter struct {
+	p      *Printer
+	logfmt bool
+
+	// The path of each referenced node
+	refPaths map[int]string
+
+	nbLeaves int
+}
+
+func (p *Printer) printFlatDocument(n *Node) {
+	fp := flatPrinter{
+		p:        p,
+		refPaths: make(map[int]string),
+	}
+
+	fp.printNode("", n)
+}
+
+func (p *Printer) printLogfmtDocument(n *Node) {
+	fp := flatPrinter{
+		p:        p,
+		logfmt:   true,
+		refPaths: make(map[int]string),
+	}
+
+	fp.printNode("", n)
+}
+
+func (fp *flatPrinter) printNode(path string, n *Node) {
+	if n.ID > 0 && n.Kind != NodeRef {
+		fp.refPaths[n.ID] = path
+	}
+
+	switch n.Kind {
+	case NodeSeq:
+		if len(n.Elements) == 0 {
+			fp.printLeaf(path, fp.p.theme.Annotation, "[]")
+		}
+
+		for i, elem := range n.Elements {
+			fp.printNode(fp.indexPath(path, i), elem)
+		}
+
+	case NodeMap:
+		if len(n.Entries) == 0 {
+			fp.printLeaf(path, fp.p.theme.Annotation, "{}")
+		}
+
+		for _, entry := range n.Entries {
+			fp.printNode(fp.keyPath(path, entry.Key), entry.Value)
+		}
+
+	case NodeStruct:
+		if len(n.Fields) == 0 {
+			fp.printLeaf(path, fp.p.theme.Annotation, "{}")
+		}
+
+		for _, field := range n.Fields {
+			fp.printNode(fp.fieldPath(path, field.Name), field.Value)
+		}
+
+	case NodeRef:
+		fp.printLeaf(path, fp.p.theme.Annotation,
+			"<ref "+pathString(fp.refPaths[n.ID])+">")
+
+	case NodeScalar:
+		fp.printScalar(path, n)
+	}
+}
+
+func (fp *flatPrinter) printScalar(path string, n *Node) {
+	switch n.Scalar {
+	case ScalarNil:
+		if fp.logfmt {
+			fp.printLeaf(path, fp.p.theme.Keyword, "null")
+		} else {
+			fp.printLeaf(path, fp.p.theme.Keyword, "nil")
+		}
+
+	case ScalarBool:
+		fp.printLeaf(path, fp.p.theme.Keyword, n.Value)
+
+	case ScalarNumber:
+		fp.printLeaf(path, fp.p.theme.Number, n.Value)
+
+	case ScalarString:
+		if fp.logfmt {
+			fp.printLeaf(path, fp.p.theme.String, n.Value)
+		} else {
+			fp.printLeaf(path, fp.p.theme.String, strconv.Quote(n.Value))
+		}
+	}
+}
+
+func (fp *flatPrinter) indexPath(path string, i int) string {
+	if fp.logfmt {
+		return joinPath(path, strconv.Itoa(i))
+	}
+
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func (fp *flatPrinter) keyPath(path string, key *Node) string {
+	var s string
+
+	switch {
+	case key.Kind != NodeScalar:
+		s = fp.p.jsonKeyString(key)
+
+	case key.Scalar == ScalarString:
+		if fp.logfmt {
+			s = key.Value
+		} else {
+			s = strconv.Quote(key.Value)
+		}
+
+	case key.Scalar == ScalarNil:
+		s = "nil"
+
+	default:
+		s = key.Value
+	}
+
+	if fp.logfmt {
+		return joinPath(path, logfmtKey(s))
+	}
+
+	return path + "[" + s + "]"
+}
+
+func (fp *flatPrinter) fieldPath(path, name string) string {
+	if fp.logfmt {
+		return joinPath(path, logfmtKey(name))
+	}
+
+	return joinPath(path, name)
+}
+
+func (fp *flatPrinter) printLeaf(path, color, value string) {
+	p := fp.p
+
+	if fp.logfmt {
+		fp.printLogfmtLeaf(path, color, value)
+		return
+	}
+
+	if fp.nbLeaves > 0 {
+		if p.inline {
+			p.printString(", ")
+		} else {
+			p.printNewline()
+			p.printLineStart()
+		}
+	}
+	fp.nbLeaves++
+
+	if path != "" {
+		p.printColoredString(p.theme.FieldName, path)
+		p.printString(" = ")
+	}
+
+	p.printColoredString(color, value)
+}
@@ -0,0 +1,24 @@
+package pp
+
+import "testing"
+
+func TestFlatSharedPointerToPointer(t *testing.T) {
+	v := &testTreeValue{N: 1}
+	pv := &v
+
+	p := NewPrinter(WithColors(false), WithFormat(FormatFlat))
+
+	tests := []struct {
+		value  any
+		output string
+	}{
+		{[]any{pv, pv}, "[0].N = 1\n[1] = <ref [0]>"},
+		{[]any{v, pv, pv}, "[0].N = 1\n[1] = <ref [0]>\n[2] = <ref [0]>"},
+	}
+
+	for _, test := range tests {
+		if s := p.String(test.value); s != test.output {
+			t.Errorf("got %q, expected %q", s, test.output)
+		}
+	}
+}
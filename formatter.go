@@ -0,0 +1,37 @@
+package pp
+
+import (
+	"fmt"
+	"io"
+)
+
+// Formatter wraps a value so that it is pretty printed when formatted with
+// the fmt package. The %v and %s verbs print the value on a single line while
+// %+v uses the normal layout, possibly on multiple lines. Other verbs are
+// handled by the fmt package as if the value was not wrapped.
+type Formatter struct {
+	printer *Printer
+	value   any
+}
+
+func Wrap(value any) Formatter {
+	return DefaultPrinter.Wrap(value)
+}
+
+func (p *Printer) Wrap(value any) Formatter {
+	return Formatter{printer: p, value: value}
+}
+
+func (f Formatter) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		if s.Flag('+') {
+			io.WriteString(s, f.printer.String(f.value))
+		} else {
+			f.printer.renderInline(s, f.value)
+		}
+
+	default:
+		fmt.Fprintf(s, fmt.FormatString(s, verb), f.value)
+	}
+}
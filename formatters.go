@@ -0,0 +1,86 @@
+package pp
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// RegisterFormatter registers a function used to format values of a specific
+// type. If the type is an interface, the function is used for all values
+// implementing it; if a value implements several interfaces, the formatter
+// registered first is used. Formatters take precedence over the formatting
+// function set with SetFormatValueFunc, which is still called if the formatter
+// returns nil.
+func (p *Printer) RegisterFormatter(t reflect.Type, fn FormatValueFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Printers share the formatter map and the list of interface types with
+	// their clones, so we never modify them in place.
+	formatters := maps.Clone(p.formatters)
+	if formatters == nil {
+		formatters = make(map[reflect.Type]FormatValueFunc)
+	}
+
+	_, registered := formatters[t]
+
+	if fn == nil {
+		delete(formatters, t)
+	} else {
+		formatters[t] = fn
+	}
+
+	// Interface types are kept in registration order so that the formatter
+	// used for a value implementing several of them does not change.
+	if t.Kind() == reflect.Interface {
+		if fn == nil {
+			p.interfaceFormatterTypes = slices.DeleteFunc(
+				slices.Clone(p.interfaceFormatterTypes),
+				func(it reflect.Type) bool { return it == t })
+		} else if !registered {
+			p.interfaceFormatterTypes = append(
+				slices.Clip(p.interfaceFormatterTypes), t)
+		}
+	}
+
+	p.formatters = formatters
+}
+
+func Register[T any](fn func(T) any) {
+	RegisterTo(&DefaultPrinter, fn)
+}
+
+func RegisterTo[T any](p *Printer, fn func(T) any) {
+	p.RegisterFormatter(reflect.TypeFor[T](), func(v reflect.Value) any {
+		value, ok := valueInterface(v)
+		if !ok {
+			return nil
+		}
+
+		tvalue, ok := value.(T)
+		if !ok {
+			return nil
+		}
+
+		return fn(tvalue)
+	})
+}
+
+func (p *Printer) lookupFormatter(t reflect.Type) FormatValueFunc {
+	if len(p.formatters) == 0 {
+		return nil
+	}
+
+	if fn, found := p.formatters[t]; found {
+		return fn
+	}
+
+	for _, it := range p.interfaceFormatterTypes {
+		if t.Implements(it) {
+			return p.formatters[it]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package pp
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testFormatterValue struct{}
+
+func (testFormatterValue) String() string { return "value" }
+func (testFormatterValue) Error() string  { return "error" }
+
+func TestFormatterInterfaceOrder(t *testing.T) {
+	p := NewPrinter(WithColors(false))
+
+	p.RegisterFormatter(reflect.TypeFor[fmt.Stringer](),
+		func(reflect.Value) any { return "stringer" })
+	p.RegisterFormatter(reflect.TypeFor[error](),
+		func(reflect.Value) any { return "error" })
+
+	for range 20 {
+		if s := p.String(testFormatterValue{}); s != `string("stringer")` {
+			t.Fatalf("got %q, expected %q", s, `string("stringer")`)
+		}
+	}
+
+	p.RegisterFormatter(reflect.TypeFor[fmt.Stringer](), nil)
+
+	if s := p.String(testFormatterValue{}); s != `string("error")` {
+		t.Errorf("got %q, expected %q", s, `string("error")`)
+	}
+}
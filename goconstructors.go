@@ -0,0 +1,92 @@
+package pp
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var bigIntType = reflect.TypeFor[big.Int]()
+
+// goConstructor returns a Go expression building a value whose state is
+// stored in unexported fields, and which therefore cannot be represented by
+// a composite literal.
+func (p *Printer) goConstructor(v reflect.Value) (string, bool) {
+	if goUnexportedType(v.Type()) {
+		return goErrorConstructor(v)
+	}
+
+	switch v.Type() {
+	case timeType:
+		t, ok := valueAs[time.Time](v)
+		if !ok {
+			return "", false
+		}
+
+		return goTimeConstructor(t), true
+
+	case bigIntType:
+		n, ok := valueAs[big.Int](v)
+		if !ok {
+			return "", false
+		}
+
+		return "*" + goBigIntConstructor(&n), true
+
+	case reflect.PointerTo(bigIntType):
+		n, ok := valueAs[*big.Int](v)
+		if !ok {
+			return "", false
+		}
+
+		return goBigIntConstructor(n), true
+	}
+
+	return "", false
+}
+
+func goErrorConstructor(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", false
+	}
+
+	err, ok := valueAs[error](v)
+	if !ok {
+		return "", false
+	}
+
+	return "errors.New(" + strconv.Quote(err.Error()) + ")", true
+}
+
+func goBigIntConstructor(n *big.Int) string {
+	if n.IsInt64() {
+		return "big.NewInt(" + n.String() + ")"
+	}
+
+	return "func() *big.Int { n, _ := new(big.Int).SetString(" +
+		strconv.Quote(n.String()) + ", 10); return n }()"
+}
+
+func goTimeConstructor(t time.Time) string {
+	var location string
+	switch loc := t.Location(); loc {
+	case time.UTC:
+		location = "time.UTC"
+	case time.Local:
+		location = "time.Local"
+	default:
+		name, offset := t.Zone()
+		location = "time.FixedZone(" + strconv.Quote(name) + ", " +
+			strconv.Itoa(offset) + ")"
+	}
+
+	return "time.Date(" +
+		strconv.Itoa(t.Year()) + ", time." + t.Month().String() + ", " +
+		strconv.Itoa(t.Day()) + ", " +
+		strconv.Itoa(t.Hour()) + ", " +
+		strconv.Itoa(t.Minute()) + ", " +
+		strconv.Itoa(t.Second()) + ", " +
+		strconv.Itoa(t.Nanosecond()) + ", " +
+		location + ")"
+}
@@ -0,0 +1,497 @@
+package pp
+
+import (
+	"go/token"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Go literal output prints values as Go expressions which can be copied into
+// source code, e.g. test fixtures. Value formatting functions are not used
+// since their output is not valid Go. Unexported fields cannot be set in a
+// composite literal and are not printed; neither are redacted fields and
+// fields whose type is skipped. Common types whose state is unexported, e.g.
+// time.Time, are printed as constructor calls; for other structures, the
+// literal is followed by a comment containing the unexported fields which are
+// set.
+//
+// Values which cannot be represented, i.e. repeated references and values of
+// unexported types of other packages, are printed as an undefined identifier
+// so that the literal fails to compile instead of silently being different
+// from the value. Errors of unexported types, e.g. the ones created by
+// fmt.Errorf, are printed as calls to errors.New with their message. Unexported types are only considered to be
+// usable if they belong to the package of the printed value, or of its
+// elements if its type is not a named type.
+
+// goPlaceholder is the identifier printed instead of values which cannot be
+// represented.
+const goPlaceholder = "unrepresentable"
+
+func (p *Printer) printGoDocument(v reflect.Value) {
+	p.goPackage = ""
+	if v.IsValid() {
+		p.goPackage = goValuePackage(v.Type())
+	}
+
+	p.printGoValue(v, nil)
+}
+
+// goValuePackage returns the package of a type, or of the type of its
+// elements if it is not a named type.
+func goValuePackage(t reflect.Type) string {
+	for t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Array, reflect.Slice, reflect.Map, reflect.Pointer:
+			t = t.Elem()
+		default:
+			return ""
+		}
+	}
+
+	return t.PkgPath()
+}
+
+func (p *Printer) printGoValue(v reflect.Value, expectedType reflect.Type) {
+	if p.overflow {
+		return
+	}
+
+	if v.Kind() == 0 {
+		p.printColoredString(p.theme.Keyword, "nil")
+		return
+	}
+
+	if p.depth >= maxRecursionDepth {
+		p.printColoredString(p.theme.Keyword, "nil")
+		p.printColoredString(p.theme.Annotation, " /* max depth exceeded */")
+		return
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+
+	p.checkContext()
+
+	if p.skippedValue(v) {
+		p.printColoredString(p.theme.Annotation,
+			"/* omitted "+p.valueTypeString(v)+" */")
+		return
+	}
+
+	if !p.propagatePanics {
+		offset, level := p.outputOffset(), p.level
+		defer func() {
+			if value := recover(); value != nil {
+				if renderAborted(value) {
+					panic(value)
+				}
+
+				p.printPanic(value, offset, level)
+			}
+		}()
+	}
+
+	if !p.inline && p.layout != LayoutExpanded && !p.goAtomicValue(v) {
+		restorePointerReferences := p.savePointerReferences()
+
+		p2 := p.inlineClone()
+		p2.printGoValue(v, expectedType)
+		p.scratch = p2.buf
+
+		if !p2.overflow {
+			p.printBytes(p.scratch)
+			return
+		}
+
+		restorePointerReferences()
+	}
+
+	if s, ok := p.goStringerValue(v); ok {
+		p.printString(s.GoString())
+		return
+	}
+
+	if s, ok := p.goConstructor(v); ok {
+		p.printString(s)
+		return
+	}
+
+	if p.goForeignType(v.Type()) {
+		comment := p.goCommentString(func(p2 *Printer) {
+			p2.initPointers(v)
+			p2.printValue(v)
+		})
+
+		p.printColoredString(p.theme.Keyword, goPlaceholder)
+		p.printColoredString(p.theme.Annotation, " /* "+comment+" */")
+		return
+	}
+
+	vt := v.Type()
+
+	switch v.Kind() {
+	case reflect.Bool:
+		p.printGoScalar(v, expectedType, strconv.FormatBool(v.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p.printGoScalar(v, expectedType, strconv.FormatInt(v.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		p.printGoScalar(v, expectedType, strconv.FormatUint(v.Uint(), 10))
+
+	case reflect.Uintptr:
+		p.printGoScalar(v, expectedType,
+			"0x"+strconv.FormatUint(v.Uint(), 16))
+
+	case reflect.Float32, reflect.Float64:
+		p.printGoScalar(v, expectedType, goFloatLiteral(v.Float(), vt.Bits()))
+
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		bits := vt.Bits() / 2
+		s := "complex(" + goFloatLiteral(real(c), bits) + ", " +
+			goFloatLiteral(imag(c), bits) + ")"
+		p.printGoScalar(v, expectedType, s)
+
+	case reflect.String:
+		p.printGoScalar(v, expectedType, strconv.Quote(v.String()))
+
+	case reflect.Array:
+		p.printGoComposite(v, func(i int) {
+			p.printGoValue(v.Index(i), vt.Elem())
+		}, v.Len())
+
+	case reflect.Slice:
+		if v.IsNil() {
+			p.printGoNil(vt, expectedType)
+			return
+		}
+
+		if p.printGoReference(v.Pointer()) {
+			return
+		}
+
+		p.printGoComposite(v, func(i int) {
+			p.printGoValue(v.Index(i), vt.Elem())
+		}, v.Len())
+
+	case reflect.Map:
+		if v.IsNil() {
+			p.printGoNil(vt, expectedType)
+			return
+		}
+
+		if p.printGoReference(v.Pointer()) {
+			return
+		}
+
+		keys := p.sortedMapKeys(v)
+
+		p.printGoComposite(v, func(i int) {
+			p.printGoValue(keys[i], vt.Key())
+			p.printString(": ")
+			p.printGoValue(v.MapIndex(keys[i]), vt.Elem())
+		}, len(keys))
+
+	case reflect.Struct:
+		var fields []structField
+		fields2, _ := p.nonZeroFields(v, p.structFields(v))
+		for _, f := range fields2 {
+			if f.IsExported() && f.redaction == "" &&
+				!p.skippedValue(f.value) {
+				fields = append(fields, f)
+			}
+		}
+
+		p.printGoComposite(v, func(i int) {
+			p.printColoredString(p.theme.FieldName, fields[i].Name)
+			p.printString(": ")
+
+			baseline := p.baselineValue
+			p.baselineValue = fields[i].baseline
+			p.printGoValue(fields[i].value, fields[i].Type)
+			p.baselineValue = baseline
+		}, len(fields))
+
+		if unexportedFields := p.goUnexportedFields(v); len(unexportedFields) > 0 {
+			p.printColoredString(p.theme.Annotation,
+				" /* "+p.goFieldsComment(unexportedFields)+" */")
+		}
+
+	case reflect.Interface:
+		if v.IsNil() {
+			p.printColoredString(p.theme.Keyword, "nil")
+			return
+		}
+
+		p.printGoValue(v.Elem(), nil)
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			p.printGoNil(vt, expectedType)
+			return
+		}
+
+		if !p.followPointer() {
+			p.printGoNil(vt, expectedType)
+			p.printColoredString(p.theme.Annotation,
+				" /* "+formatPointerAddress(v.Pointer())+" */")
+			return
+		}
+
+		if p.printGoReference(v.Pointer()) {
+			return
+		}
+
+		p.pointerDepth++
+		defer func() { p.pointerDepth-- }()
+
+		switch ev := v.Elem(); ev.Kind() {
+		case reflect.Array, reflect.Slice, reflect.Map, reflect.Struct:
+			p.printByte('&')
+			p.printGoValue(ev, nil)
+
+		default:
+			// There is no literal syntax for pointers to other values, so we
+			// use a function literal returning the address of a variable.
+			p.printString("func() ")
+			p.printColoredString(p.theme.Type, p.goTypeString(vt))
+			p.printString(" { v := ")
+			p.printGoValue(ev, nil)
+			p.printString("; return &v }()")
+		}
+
+	default:
+		// Functions, channels and unsafe pointers cannot be represented
+		p.printColoredString(p.theme.Keyword, "nil")
+		p.printColoredString(p.theme.Annotation,
+			" /* "+p.goTypeString(vt)+" */")
+	}
+}
+
+func (p *Printer) printGoScalar(v reflect.Value, expectedType reflect.Type, s string) {
+	var color string
+	switch v.Kind() {
+	case reflect.Bool:
+		color = p.theme.Keyword
+	case reflect.String:
+		color = p.theme.String
+	default:
+		color = p.theme.Number
+	}
+
+	if goUntypedLiteral(v.Type(), expectedType) {
+		p.printColoredString(color, s)
+		return
+	}
+
+	p.printColoredString(p.theme.Type, p.goTypeString(v.Type()))
+	p.printByte('(')
+	p.printColoredString(color, s)
+	p.printByte(')')
+}
+
+func (p *Printer) printGoNil(t, expectedType reflect.Type) {
+	if t == expectedType {
+		p.printColoredString(p.theme.Keyword, "nil")
+		return
+	}
+
+	typeString := p.goTypeString(t)
+	if t.Kind() == reflect.Pointer {
+		typeString = "(" + typeString + ")"
+	}
+
+	p.printColoredString(p.theme.Type, typeString)
+	p.printByte('(')
+	p.printColoredString(p.theme.Keyword, "nil")
+	p.printByte(')')
+}
+
+func (p *Printer) printGoReference(ptr uintptr) bool {
+	first, n := p.pointerReference(ptr)
+	if n == 0 {
+		return false
+	}
+
+	if first {
+		p.printColoredString(p.theme.Annotation,
+			"/* #"+strconv.Itoa(n)+" */ ")
+		return false
+	}
+
+	// References cannot be expressed in a literal
+	p.printColoredString(p.theme.Keyword, goPlaceholder)
+	p.printColoredString(p.theme.Annotation,
+		" /* #"+strconv.Itoa(n)+" */")
+
+	return true
+}
+
+func (p *Printer) printGoComposite(v reflect.Value, printElement func(int), n int) {
+	p.printColoredString(p.theme.Type, p.goTypeString(v.Type()))
+
+	if n == 0 {
+		p.printString("{}")
+		return
+	}
+
+	p.printByte('{')
+	if !p.inline {
+		p.printNewline()
+	}
+	p.level++
+
+	for i := range n {
+		if !p.inline {
+			p.printLineStart()
+		}
+
+		printElement(i)
+
+		if p.inline {
+			if i < n-1 {
+				p.printString(", ")
+			}
+		} else {
+			p.printByte(',')
+			p.printNewline()
+		}
+	}
+
+	p.level--
+	if !p.inline {
+		p.printLineStart()
+	}
+	p.printByte('}')
+}
+
+// goUnexportedFields returns the unexported fields of a structure which are
+// set, i.e. the information lost when printing it as a literal.
+func (p *Printer) goUnexportedFields(v reflect.Value) []structField {
+	var fields []structField
+
+	for _, f := range p.structFields(v) {
+		if !f.IsExported() && !f.value.IsZero() {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func (p *Printer) goFieldsComment(fields []structField) string {
+	var buf strings.Builder
+
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(f.label())
+		buf.WriteString(": ")
+
+		if f.redaction != "" {
+			buf.WriteString(f.redaction)
+			continue
+		}
+
+		buf.WriteString(p.goCommentString(func(p2 *Printer) {
+			p2.initPointers(f.value)
+			p2.printFieldValue(f)
+		}))
+	}
+
+	return buf.String()
+}
+
+// goCommentString returns the output of a print function using the native
+// format on a single line, to be included in a comment.
+func (p *Printer) goCommentString(print func(*Printer)) string {
+	p2 := p.clone()
+	p2.buf = nil
+	p2.format = FormatNative
+	p2.inline = true
+	p2.measureWidth = false
+	p2.colors = false
+
+	print(p2)
+
+	return strings.ReplaceAll(string(p2.buf), "*/", "* /")
+}
+
+// goUnexportedType returns whether a type, or the type it points to, is an
+// unexported type.
+func goUnexportedType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer && t.Name() == "" {
+		t = t.Elem()
+	}
+
+	return t.Name() != "" && t.PkgPath() != "" && !token.IsExported(t.Name())
+}
+
+// goForeignType returns whether a type, or the type it points to, is an
+// unexported type of another package than the package of the printed value,
+// and therefore cannot be used in a literal.
+func (p *Printer) goForeignType(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer && t.Name() == "" {
+		t = t.Elem()
+	}
+
+	return goUnexportedType(t) && t.PkgPath() != p.goPackage
+}
+
+func (p *Printer) goAtomicValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.Struct,
+		reflect.Pointer, reflect.Interface:
+		return false
+	}
+
+	return true
+}
+
+func (p *Printer) goTypeString(t reflect.Type) string {
+	return strings.ReplaceAll(t.String(), "interface {}", "any")
+}
+
+func goUntypedLiteral(t, expectedType reflect.Type) bool {
+	if t == expectedType {
+		return true
+	}
+
+	if t.PkgPath() != "" {
+		return false
+	}
+
+	// Untyped constants default to these types when there is no expected
+	// type.
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Float64, reflect.Complex128,
+		reflect.String:
+		return true
+	}
+
+	return false
+}
+
+func goFloatLiteral(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "math.NaN()"
+	case math.IsInf(f, 1):
+		return "math.Inf(1)"
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)"
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	if !strings.ContainsAny(s, ".eEn") {
+		s += ".0"
+	}
+
+	return s
+}
@@ -0,0 +1,104 @@
+package pp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testGoValue struct {
+	Name  string
+	count int
+}
+
+type testGoOuterValue struct {
+	Value testGoValue
+	id    int
+}
+
+// testUnexportedField returns the representation of the value of an
+// unexported field, which can only be read with unsafe access.
+func testUnexportedField(s string) string {
+	if unsafeAccess {
+		return s
+	}
+
+	return "<unexported>"
+}
+
+func TestGoUnexportedState(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithFormat(FormatGo))
+
+	n, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	tests := []struct {
+		value  any
+		output string
+	}{
+		{time.Date(2024, 3, 1, 12, 30, 0, 5, time.UTC),
+			"time.Date(2024, time.March, 1, 12, 30, 0, 5, time.UTC)"},
+		{time.Date(2024, 3, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600)),
+			`time.Date(2024, time.March, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))`},
+		{big.NewInt(7), "big.NewInt(7)"},
+		{*big.NewInt(-7), "*big.NewInt(-7)"},
+		{n, `func() *big.Int { n, _ := new(big.Int).SetString("123456789012345678901234567890", 10); return n }()`},
+		{errors.New("a */ b"), `errors.New("a */ b")`},
+		{fmt.Errorf("a: %w", errors.New("b")), `errors.New("a: b")`},
+		{testGoValue{Name: "a"}, `pp.testGoValue{Name: "a"}`},
+		{testGoValue{Name: "a", count: 2},
+			`pp.testGoValue{Name: "a"} /* count: ` + testUnexportedField("2") + ` */`},
+		{testGoOuterValue{Value: testGoValue{Name: "a"}, id: 3},
+			`pp.testGoOuterValue{Value: pp.testGoValue{Name: "a"}} /* id: ` +
+				testUnexportedField("3") + ` */`},
+	}
+
+	for _, test := range tests {
+		if s := p.String(test.value); s != test.output {
+			t.Errorf("got %q, expected %q", s, test.output)
+		}
+	}
+}
+
+func TestGoUnrepresentableValues(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithFormat(FormatGo),
+		WithLayout(LayoutCompact))
+
+	v := &testGoValue{Name: "a"}
+
+	tests := []struct {
+		value  any
+		output string
+	}{
+		{[]*testGoValue{v, v},
+			`[]*pp.testGoValue{/* #1 */ &pp.testGoValue{Name: "a"}, unrepresentable /* #1 */}`},
+		{[]any{&testGoValue{Name: "*/"}},
+			`[]any{unrepresentable /* &pp.testGoValue({Name: "* /", count: ` +
+				testUnexportedField("0") + `}) */}`},
+	}
+
+	for _, test := range tests {
+		if s := p.String(test.value); s != test.output {
+			t.Errorf("got %q, expected %q", s, test.output)
+		}
+	}
+}
+
+type testGoList struct {
+	Next *testGoList
+}
+
+func TestGoMaxDepth(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithFormat(FormatGo))
+
+	var l *testGoList
+	for range maxRecursionDepth {
+		l = &testGoList{Next: l}
+	}
+
+	if s := p.String(l); !strings.Contains(s, "Next: nil /* max depth exceeded */") {
+		t.Errorf("missing max depth marker in %q", s[max(0, len(s)-200):])
+	}
+}
@@ -0,0 +1,175 @@
+package pp
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The maximum number of characters of a scalar value shown in a graph node,
+// and the maximum number of scalar entries listed in the label of a node.
+const (
+	graphMaxValueLength = 32
+	graphMaxEntries     = 16
+)
+
+type grapher struct {
+	p   *Printer
+	buf strings.Builder
+
+	nbNodes int
+}
+
+func Graph(w io.Writer, value any) error {
+	return DefaultPrinter.Graph(w, value)
+}
+
+// Graph writes a Graphviz DOT representation of the object graph of a value.
+// Structures, maps and sequences are represented by nodes listing their scalar
+// entries; pointers, slices and map entries referencing other composite values
+// are represented by edges, so that shared and cyclic values appear as such.
+func (p *Printer) Graph(w io.Writer, value any) error {
+	p = p.snapshot(nil)
+
+	p.reset(value)
+	defer p.releaseBuffer()
+
+	g := grapher{p: p}
+
+	g.buf.WriteString("digraph {\n")
+	g.buf.WriteString("  node [shape=box, fontname=\"monospace\"];\n")
+	g.writeNode(p.buildNode(reflectValue(value)))
+	g.buf.WriteString("}\n")
+
+	_, err := io.WriteString(w, g.buf.String())
+	return err
+}
+
+// writeNode writes a node and the nodes reachable from it, and returns its
+// identifier.
+func (g *grapher) writeNode(n *Node) string {
+	if n.Kind == NodeRef {
+		return "r" + strconv.Itoa(n.ID)
+	}
+
+	var id string
+	if n.ID > 0 {
+		id = "r" + strconv.Itoa(n.ID)
+	} else {
+		g.nbNodes++
+		id = "n" + strconv.Itoa(g.nbNodes)
+	}
+
+	var label strings.Builder
+	var edges []string
+
+	if n.Type != "" {
+		label.WriteString(n.Type)
+		label.WriteString("\n")
+	}
+
+	if n.composite() {
+		nbScalars := 0
+
+		writeChild := func(name string, child *Node) {
+			if graphScalarNode(child) {
+				nbScalars++
+				if nbScalars <= graphMaxEntries {
+					label.WriteString(name + ": " + g.scalarString(child))
+					label.WriteString("\n")
+				}
+
+				return
+			}
+
+			childId := g.writeNode(child)
+			edges = append(edges,
+				id+" -> "+childId+" [label="+dotString(name)+"];\n")
+		}
+
+		for i, elem := range n.Elements {
+			writeChild("["+strconv.Itoa(i)+"]", elem)
+		}
+
+		for _, entry := range n.Entries {
+			writeChild(g.scalarString(entry.Key), entry.Value)
+		}
+
+		for _, field := range n.Fields {
+			writeChild(field.Name, field.Value)
+		}
+
+		if nbScalars > graphMaxEntries {
+			label.WriteString("… (+" +
+				strconv.Itoa(nbScalars-graphMaxEntries) + " more)\n")
+		}
+	} else {
+		label.WriteString(g.scalarString(n))
+		label.WriteString("\n")
+	}
+
+	g.buf.WriteString("  " + id + " [label=" + dotLabel(label.String()) + "];\n")
+	for _, edge := range edges {
+		g.buf.WriteString("  " + edge)
+	}
+
+	return id
+}
+
+func (g *grapher) scalarString(n *Node) string {
+	var s string
+
+	switch {
+	case n.Kind != NodeScalar:
+		s = n.Type
+	case n.Scalar == ScalarNil:
+		s = "nil"
+	case n.Scalar == ScalarString:
+		s = strconv.Quote(n.Value)
+	default:
+		s = n.Value
+	}
+
+	if r := []rune(s); len(r) > graphMaxValueLength {
+		s = string(r[:graphMaxValueLength-1]) + "…"
+	}
+
+	return s
+}
+
+// graphScalarNode indicates whether a node is printed in the label of its
+// parent instead of having its own node. Referenced scalars get their own node
+// so that sharing is visible.
+func graphScalarNode(n *Node) bool {
+	return n.Kind == NodeScalar && n.ID == 0
+}
+
+// dotLabel returns a quoted DOT label whose lines are left-aligned.
+func dotLabel(s string) string {
+	return quoteDOTString(s, `\l`)
+}
+
+func dotString(s string) string {
+	return quoteDOTString(s, `\n`)
+}
+
+func quoteDOTString(s, newline string) string {
+	var buf strings.Builder
+
+	buf.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(newline)
+		default:
+			buf.WriteRune(c)
+		}
+	}
+	buf.WriteByte('"')
+
+	return buf.String()
+}
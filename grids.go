@@ -0,0 +1,167 @@
+package pp
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The maximum number of characters of a string for a sequence of sequences of
+// strings to be printed as a grid.
+const gridMaxStringLength = 16
+
+// gridSequence returns whether a sequence is a sequence of sequences of
+// scalar values which can be printed as a grid.
+func gridSequence(v reflect.Value) bool {
+	rowType := v.Type().Elem()
+	if rowType.Kind() != reflect.Slice && rowType.Kind() != reflect.Array {
+		return false
+	}
+
+	switch rowType.Elem().Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+
+	case reflect.String:
+		for i := range v.Len() {
+			row := v.Index(i)
+			for j := range row.Len() {
+				s := row.Index(j).String()
+				if utf8.RuneCountInString(s) > gridMaxStringLength {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// printGrid prints a sequence of sequences with one row per line, elements
+// being aligned in columns. Numbers are aligned on the right and strings on
+// the left. Row and column indices are printed if the printer is configured
+// to do so.
+func (p *Printer) printGrid(v reflect.Value) {
+	nbRows := p.nbShownElements(v.Len())
+
+	cells := make([][]string, nbRows)
+	rowLengths := make([]int, nbRows)
+	var widths []int
+
+	for i := range nbRows {
+		row := v.Index(i)
+
+		n := row.Len()
+		if row.Kind() == reflect.Slice && row.IsNil() {
+			n = 0
+		}
+
+		rowLengths[i] = n
+		cells[i] = make([]string, p.nbShownElements(n))
+		for j := range cells[i] {
+			s := p.plainString(row.Index(j))
+			cells[i][j] = s
+
+			if j >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], utf8.RuneCountInString(s))
+		}
+	}
+
+	rightAligned := v.Type().Elem().Elem().Kind() != reflect.String
+	color := p.theme.Number
+	if !rightAligned {
+		color = p.theme.String
+	}
+
+	indexWidth := len(strconv.Itoa(nbRows - 1))
+
+	p.printByte('[')
+	p.printNewline()
+	p.level++
+
+	if p.matrixIndices {
+		var buf strings.Builder
+		buf.WriteString(strings.Repeat(" ", indexWidth+2))
+		for j, width := range widths {
+			if j > 0 {
+				buf.WriteString("  ")
+			}
+
+			buf.WriteString(padString(strconv.Itoa(j), width, true))
+		}
+
+		p.printLineStart()
+		p.printColoredString(p.theme.Annotation,
+			strings.TrimRight(buf.String(), " "))
+		p.printNewline()
+	}
+
+	for i, row := range cells {
+		p.printLineStart()
+
+		if p.matrixIndices {
+			p.printColoredString(p.theme.Annotation,
+				padString(strconv.Itoa(i), indexWidth, true)+" ")
+		}
+
+		p.printByte('[')
+		for j, cell := range row {
+			if j > 0 {
+				p.printString(", ")
+			}
+
+			p.printColoredString(color, padString(cell, widths[j], rightAligned))
+		}
+
+		if len(row) < rowLengths[i] {
+			if len(row) > 0 {
+				p.printString(", ")
+			}
+
+			p.printColoredString(p.theme.Annotation,
+				"… (+"+strconv.Itoa(rowLengths[i]-len(row))+" more)")
+		}
+		p.printString("],")
+		p.printNewline()
+	}
+
+	if nbRows < v.Len() {
+		p.printRemainingElements(v.Len() - nbRows)
+	}
+
+	p.level--
+	p.printLineStart()
+	p.printByte(']')
+}
+
+// plainString returns the inline representation of a value without colors.
+func (p *Printer) plainString(v reflect.Value) string {
+	p2 := p.clone()
+	p2.buf = nil
+	p2.inline = true
+	p2.colors = false
+	p2.printValue(v)
+
+	return string(p2.buf)
+}
+
+func padString(s string, width int, right bool) string {
+	padding := width - utf8.RuneCountInString(s)
+	if padding <= 0 {
+		return s
+	}
+
+	if right {
+		return strings.Repeat(" ", padding) + s
+	}
+
+	return s + strings.Repeat(" ", padding)
+}
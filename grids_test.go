@@ -0,0 +1,15 @@
+package pp
+
+import "testing"
+
+func TestGridMaxElements(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithMatrixGrid(true),
+		WithMaxElements(2))
+
+	value := [][]int{{1, 2, 3, 4}, {5, 6}, {7, 8, 9}}
+	output := "[][]int([\n  [1, 2, … (+2 more)],\n  [5, 6],\n  … (+1 more)\n])"
+
+	if s := p.String(value); s != output {
+		t.Errorf("got %q, expected %q", s, output)
+	}
+}
@@ -0,0 +1,116 @@
+package pp
+
+import (
+	"html"
+	"strconv"
+)
+
+// HTML documents are made of nested <details> elements whose summary contains
+// the type of the value and the number of its entries, so that large values
+// can be explored in a browser. Elements have "pp-*" classes so that they can
+// be styled.
+
+func (p *Printer) printHTMLDocument(n *Node) {
+	// The label is part of the document
+	summary := ""
+	if labelString := p.labelString(p.label...); labelString != "" {
+		summary = `<span class="pp-label">` + html.EscapeString(labelString) +
+			"</span> "
+	}
+	p.headerPrinted = true
+
+	p.printHTMLLine(`<div class="pp">`)
+	p.level++
+	p.printHTMLNode(summary, n, true)
+	p.level--
+	p.printHTMLLine(`</div>`)
+}
+
+func (p *Printer) printHTMLNode(summary string, n *Node, open bool) {
+	if n.Type != "" {
+		summary += `<span class="pp-type">` + html.EscapeString(n.Type) +
+			"</span> "
+	}
+
+	var id string
+	if n.ID > 0 && n.Kind != NodeRef {
+		id = ` id="pp-ref-` + strconv.Itoa(n.ID) + `"`
+	}
+
+	if !n.composite() {
+		p.printHTMLLine(`<div class="pp-entry"` + id + `>` + summary +
+			p.htmlScalarString(n) + `</div>`)
+		return
+	}
+
+	var openAttr string
+	if open {
+		openAttr = " open"
+	}
+
+	count := strconv.Itoa(n.nbChildren())
+	if n.nbChildren() == 1 {
+		count += " entry"
+	} else {
+		count += " entries"
+	}
+
+	p.printHTMLLine(`<details class="pp-entry"` + id + openAttr + `>`)
+	p.level++
+	p.printHTMLLine(`<summary>` + summary +
+		`<span class="pp-count">(` + count + `)</span></summary>`)
+
+	printChild := func(key string, child *Node) {
+		p.printHTMLNode(`<span class="pp-key">`+html.EscapeString(key)+
+			"</span>: ", child, false)
+	}
+
+	for i, elem := range n.Elements {
+		printChild(strconv.Itoa(i), elem)
+	}
+
+	for _, entry := range n.Entries {
+		printChild(p.jsonKeyString(entry.Key), entry.Value)
+	}
+
+	for _, field := range n.Fields {
+		printChild(field.Name, field.Value)
+	}
+
+	p.level--
+	p.printHTMLLine(`</details>`)
+}
+
+func (p *Printer) htmlScalarString(n *Node) string {
+	if n.Kind == NodeRef {
+		ref := strconv.Itoa(n.ID)
+		return `<a class="pp-reference" href="#pp-ref-` + ref + `">#` + ref +
+			`</a>`
+	}
+
+	switch n.Scalar {
+	case ScalarNil:
+		return `<span class="pp-keyword">nil</span>`
+	case ScalarBool:
+		return `<span class="pp-keyword">` + n.Value + `</span>`
+	case ScalarNumber:
+		return `<span class="pp-number">` + n.Value + `</span>`
+	case ScalarString:
+		return `<span class="pp-string">` +
+			html.EscapeString(strconv.Quote(n.Value)) + `</span>`
+	}
+
+	return ""
+}
+
+// printHTMLLine prints a line of the document. Lines are separated by newline
+// characters but the last one is not terminated, the document printer adding
+// the final newline itself.
+func (p *Printer) printHTMLLine(s string) {
+	if len(p.buf) > 0 || p.written > 0 {
+		p.printNewline()
+	}
+
+	p.printLineStart()
+	p.printString(s)
+}
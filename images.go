@@ -0,0 +1,156 @@
+package pp
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+)
+
+var (
+	imageType               = reflect.TypeFor[image.Image]()
+	imageRectangleType      = reflect.TypeFor[image.Rectangle]()
+	imageSummaryType        = reflect.TypeFor[imageSummary]()
+	imagePreviewSummaryType = reflect.TypeFor[imagePreviewSummary]()
+)
+
+// imageSummary and imagePreviewSummary are the representations of images,
+// depending on whether previews are enabled or not. They are printed with the
+// type name of the image instead of their own.
+
+type imageSummary struct {
+	Bounds     image.Rectangle
+	ColorModel string
+
+	typeName string `pp:"-"`
+}
+
+type imagePreviewSummary struct {
+	Bounds     image.Rectangle
+	ColorModel string
+	Preview    []string
+
+	typeName string `pp:"-"`
+}
+
+// Characters used for previews, from the darkest to the lightest
+const imagePreviewRamp = " .:-=+*#%@"
+
+func (p *Printer) formatImageValue(v reflect.Value) any {
+	// Rectangles implement image.Image but are printed as rectangles
+	if v.Type() == imageRectangleType {
+		return nil
+	}
+
+	var img image.Image
+
+	if v.Type().Implements(imageType) {
+		value, ok := valueInterface(v)
+		if !ok {
+			return nil
+		}
+
+		img, _ = value.(image.Image)
+	} else if v.CanAddr() && reflect.PointerTo(v.Type()).Implements(imageType) {
+		value, ok := valueInterface(v.Addr())
+		if !ok {
+			return nil
+		}
+
+		img, _ = value.(image.Image)
+	}
+
+	if img == nil {
+		return nil
+	}
+
+	if preview := imagePreview(img, p.imagePreviewWidth); preview != nil {
+		return imagePreviewSummary{
+			Bounds:     img.Bounds(),
+			ColorModel: colorModelName(img.ColorModel()),
+			Preview:    preview,
+
+			typeName: p.valueTypeString(v),
+		}
+	}
+
+	return imageSummary{
+		Bounds:     img.Bounds(),
+		ColorModel: colorModelName(img.ColorModel()),
+
+		typeName: p.valueTypeString(v),
+	}
+}
+
+func colorModelName(model color.Model) string {
+	models := []struct {
+		model color.Model
+		name  string
+	}{
+		{color.RGBAModel, "RGBA"},
+		{color.RGBA64Model, "RGBA64"},
+		{color.NRGBAModel, "NRGBA"},
+		{color.NRGBA64Model, "NRGBA64"},
+		{color.AlphaModel, "Alpha"},
+		{color.Alpha16Model, "Alpha16"},
+		{color.GrayModel, "Gray"},
+		{color.Gray16Model, "Gray16"},
+		{color.CMYKModel, "CMYK"},
+		{color.YCbCrModel, "YCbCr"},
+		{color.NYCbCrAModel, "NYCbCrA"},
+	}
+
+	for _, m := range models {
+		if m.model == model {
+			return m.name
+		}
+	}
+
+	if _, ok := model.(color.Palette); ok {
+		return "Palette"
+	}
+
+	return reflect.TypeOf(model).String()
+}
+
+// imagePreview returns a low resolution ASCII representation of an image,
+// one string per line. Characters being about twice as high as they are
+// wide, each character represents a cell twice as high as it is wide.
+func imagePreview(img image.Image, width int) []string {
+	bounds := img.Bounds()
+	if width <= 0 || bounds.Empty() {
+		return nil
+	}
+
+	width = min(width, bounds.Dx())
+
+	cellWidth := float64(bounds.Dx()) / float64(width)
+	height := max(int(float64(bounds.Dy())/(cellWidth*2)), 1)
+	cellHeight := float64(bounds.Dy()) / float64(height)
+
+	lines := make([]string, height)
+
+	for y := range height {
+		var buf strings.Builder
+
+		for x := range width {
+			px := bounds.Min.X + int((float64(x)+0.5)*cellWidth)
+			py := bounds.Min.Y + int((float64(y)+0.5)*cellHeight)
+
+			c := img.At(px, py)
+			gray := color.GrayModel.Convert(c).(color.Gray).Y
+
+			// Transparent pixels are considered dark
+			if _, _, _, a := c.RGBA(); a < 0x8000 {
+				gray = 0
+			}
+
+			i := int(gray) * (len(imagePreviewRamp) - 1) / 255
+			buf.WriteByte(imagePreviewRamp[i])
+		}
+
+		lines[y] = buf.String()
+	}
+
+	return lines
+}
@@ -0,0 +1,183 @@
+package pp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+func (p *Printer) printJSONNode(n *Node) {
+	switch n.Kind {
+	case NodeScalar:
+		if n.ID > 0 {
+			p.printJSONObjectStart()
+			p.printJSONMember("$id", "#"+strconv.Itoa(n.ID), false)
+			p.printJSONKey("$value")
+			p.printJSONScalar(n)
+			p.printJSONEntryEnd(true)
+			p.printJSONObjectEnd()
+		} else {
+			p.printJSONScalar(n)
+		}
+
+	case NodeRef:
+		p.printJSONObjectStart()
+		p.printJSONMember("$ref", "#"+strconv.Itoa(n.ID), true)
+		p.printJSONObjectEnd()
+
+	case NodeSeq:
+		if n.ID > 0 {
+			p.printJSONObjectStart()
+			p.printJSONMember("$id", "#"+strconv.Itoa(n.ID), false)
+			p.printJSONKey("$values")
+			p.printJSONArray(n)
+			p.printJSONEntryEnd(true)
+			p.printJSONObjectEnd()
+		} else {
+			p.printJSONArray(n)
+		}
+
+	case NodeMap, NodeStruct:
+		p.printJSONObject(n)
+	}
+}
+
+func (p *Printer) printJSONScalar(n *Node) {
+	switch n.Scalar {
+	case ScalarNil:
+		p.printColoredString(p.theme.Keyword, "null")
+	case ScalarBool:
+		p.printColoredString(p.theme.Keyword, n.Value)
+	case ScalarNumber:
+		p.printColoredString(p.theme.Number, n.Value)
+	case ScalarString:
+		p.printColoredString(p.theme.String, jsonString(n.Value))
+	}
+}
+
+func (p *Printer) printJSONArray(n *Node) {
+	if len(n.Elements) == 0 {
+		p.printDelimiter("[]")
+		return
+	}
+
+	p.printDelimiter("[")
+	if !p.inline {
+		p.printNewline()
+	}
+	p.level++
+
+	for i, elem := range n.Elements {
+		if !p.inline {
+			p.printLineStart()
+		}
+		p.printJSONNode(elem)
+		p.printJSONEntryEnd(i == len(n.Elements)-1)
+	}
+
+	p.level--
+	if !p.inline {
+		p.printLineStart()
+	}
+	p.printDelimiter("]")
+}
+
+func (p *Printer) printJSONObject(n *Node) {
+	nbMembers := n.nbChildren()
+
+	if nbMembers == 0 && n.ID == 0 {
+		p.printDelimiter("{}")
+		return
+	}
+
+	p.printJSONObjectStart()
+
+	if n.ID > 0 {
+		p.printJSONMember("$id", "#"+strconv.Itoa(n.ID), nbMembers == 0)
+	}
+
+	for i, entry := range n.Entries {
+		p.printJSONKey(p.jsonKeyString(entry.Key))
+		p.printJSONNode(entry.Value)
+		p.printJSONEntryEnd(i == nbMembers-1)
+	}
+
+	for i, field := range n.Fields {
+		p.printJSONKey(field.Name)
+		p.printJSONNode(field.Value)
+		p.printJSONEntryEnd(i == nbMembers-1)
+	}
+
+	p.printJSONObjectEnd()
+}
+
+func (p *Printer) printJSONObjectStart() {
+	p.printDelimiter("{")
+	if !p.inline {
+		p.printNewline()
+	}
+	p.level++
+}
+
+func (p *Printer) printJSONObjectEnd() {
+	p.level--
+	if !p.inline {
+		p.printLineStart()
+	}
+	p.printDelimiter("}")
+}
+
+func (p *Printer) printJSONKey(key string) {
+	if !p.inline {
+		p.printLineStart()
+	}
+	p.printColoredString(p.fieldNameColor(), jsonString(key))
+	p.printString(": ")
+}
+
+func (p *Printer) printJSONMember(key, value string, last bool) {
+	p.printJSONKey(key)
+	p.printColoredString(p.theme.Annotation, jsonString(value))
+	p.printJSONEntryEnd(last)
+}
+
+func (p *Printer) printJSONEntryEnd(last bool) {
+	if !last {
+		p.printByte(',')
+	}
+
+	if !p.inline {
+		p.printNewline()
+	} else if !last {
+		p.printByte(' ')
+	}
+}
+
+func (p *Printer) jsonKeyString(key *Node) string {
+	if key.Kind == NodeScalar {
+		if key.Scalar == ScalarNil {
+			return "null"
+		}
+
+		return key.Value
+	}
+
+	// Composite keys are represented by their own JSON representation.
+	p2 := p.clone()
+	p2.buf = nil
+	p2.inline = true
+	p2.colors = false
+	p2.printJSONNode(key)
+
+	return string(p2.buf)
+}
+
+func jsonString(s string) string {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+}
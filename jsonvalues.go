@@ -0,0 +1,123 @@
+package pp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSON documents embedded in values, either as json.RawMessage values or, if
+// enabled, as strings, are converted to a node tree and printed as JSON
+// instead of byte slices or escaped strings.
+
+var rawMessageType = reflect.TypeFor[json.RawMessage]()
+
+func (p *Printer) jsonValueNode(v reflect.Value) *Node {
+	var data []byte
+
+	switch {
+	case v.Type() == rawMessageType:
+		data = v.Bytes()
+
+	case p.expandJSONStrings && v.Kind() == reflect.String:
+		// Only objects and arrays are expanded: other JSON values are strings
+		// which are not worth expanding.
+		s := strings.TrimSpace(v.String())
+		if s == "" || (s[0] != '{' && s[0] != '[') {
+			return nil
+		}
+
+		data = []byte(s)
+
+	default:
+		return nil
+	}
+
+	n, err := parseJSONNode(data)
+	if err != nil {
+		return nil
+	}
+
+	return n
+}
+
+func parseJSONNode(data []byte) (*Node, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	n, err := decodeJSONNode(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("trailing data after JSON value")
+	}
+
+	return n, nil
+}
+
+func decodeJSONNode(d *json.Decoder) (*Node, error) {
+	token, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		var n Node
+
+		switch t {
+		case '[':
+			n.Kind = NodeSeq
+
+			for d.More() {
+				value, err := decodeJSONNode(d)
+				if err != nil {
+					return nil, err
+				}
+
+				n.Elements = append(n.Elements, value)
+			}
+
+		case '{':
+			n.Kind = NodeMap
+
+			for d.More() {
+				keyToken, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				key := scalarNode(ScalarString, keyToken.(string))
+
+				value, err := decodeJSONNode(d)
+				if err != nil {
+					return nil, err
+				}
+
+				n.Entries = append(n.Entries, NodeEntry{Key: key, Value: value})
+			}
+		}
+
+		// Closing delimiter
+		if _, err := d.Token(); err != nil {
+			return nil, err
+		}
+
+		return &n, nil
+
+	case json.Number:
+		return scalarNode(ScalarNumber, t.String()), nil
+	case string:
+		return scalarNode(ScalarString, t), nil
+	case bool:
+		return scalarNode(ScalarBool, strconv.FormatBool(t)), nil
+	}
+
+	return scalarNode(ScalarNil, ""), nil
+}
@@ -0,0 +1,82 @@
+package pp
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// limitWriter writes at most a maximum number of bytes and counts the bytes
+// discarded after that, so that a truncation marker can be written at the end
+// of the output.
+type limitWriter struct {
+	w      io.Writer
+	max    int
+	colors bool
+
+	written int
+	omitted int
+	endsEOL bool
+}
+
+func (w *limitWriter) Write(data []byte) (int, error) {
+	if w.omitted > 0 {
+		w.omitted += len(data)
+		return len(data), nil
+	}
+
+	n := len(data)
+	if w.written+n > w.max {
+		n = truncationLength(data, w.max-w.written)
+		w.omitted = len(data) - n
+	}
+
+	if n > 0 {
+		written, err := w.w.Write(data[:n])
+		w.written += written
+		if err != nil {
+			return written, err
+		}
+
+		w.endsEOL = data[n-1] == '\n'
+	}
+
+	return len(data), nil
+}
+
+// finish writes the truncation marker if part of the output was discarded.
+func (w *limitWriter) finish() error {
+	if w.omitted == 0 {
+		return nil
+	}
+
+	var marker string
+	if w.colors {
+		marker = "\x1b[0m"
+	}
+	if w.written > 0 && !w.endsEOL {
+		marker += "\n"
+	}
+	marker += "… (" + strconv.Itoa(w.omitted) + " bytes omitted)\n"
+
+	_, err := io.WriteString(w.w, marker)
+	return err
+}
+
+// truncationLength returns the largest length lower or equal to n at which
+// data can be cut without splitting a UTF-8 sequence or a terminal escape
+// sequence.
+func truncationLength(data []byte, n int) int {
+	for n > 0 && n < len(data) && !utf8.RuneStart(data[n]) {
+		n--
+	}
+
+	if esc := bytes.LastIndexByte(data[:n], '\x1b'); esc >= 0 {
+		if bytes.IndexByte(data[esc:n], 'm') < 0 {
+			n = esc
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,47 @@
+package pp
+
+import (
+	"bytes"
+	"io"
+)
+
+// linePrefixWriter inserts the result of a line prefix function at the
+// beginning of each line written. Line numbers start at 1.
+type linePrefixWriter struct {
+	w  io.Writer
+	fn LinePrefixFunc
+
+	lineNo  int
+	midLine bool
+}
+
+func (w *linePrefixWriter) Write(data []byte) (int, error) {
+	n := 0
+
+	for len(data) > 0 {
+		if !w.midLine {
+			w.lineNo++
+			if _, err := io.WriteString(w.w, w.fn(w.lineNo)); err != nil {
+				return n, err
+			}
+
+			w.midLine = true
+		}
+
+		end := len(data)
+		if eol := bytes.IndexByte(data, '\n'); eol >= 0 {
+			end = eol + 1
+			w.midLine = false
+		}
+
+		written, err := w.w.Write(data[:end])
+		n += written
+		if err != nil {
+			return n, err
+		}
+
+		data = data[end:]
+	}
+
+	return n, nil
+}
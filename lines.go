@@ -0,0 +1,95 @@
+package pp
+
+import (
+	"bytes"
+	"iter"
+)
+
+func Lines(value any, label ...any) iter.Seq[string] {
+	return DefaultPrinter.Lines(value, label...)
+}
+
+// Lines returns a sequence of the lines of the representation of a value,
+// without newline characters. Lines are rendered as the sequence is iterated
+// on, and rendering stops as soon as iteration does.
+func (p *Printer) Lines(value any, label ...any) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		label, opts := splitOptions(label)
+		p2 := p.snapshot(opts)
+
+		p2.reset(value)
+		defer p2.releaseBuffer()
+
+		defer func() {
+			if value := recover(); value != nil {
+				if yp, ok := value.(yieldPanic); ok {
+					value = yp.value
+				}
+
+				panic(value)
+			}
+		}()
+
+		w := lineWriter{p: p2, yield: yield}
+		p2.render(nil, &w, value, label...)
+		w.finish()
+	}
+}
+
+// yieldPanic is the value of the panic used to stop rendering when the body
+// of the loop iterating on lines panics. Printers never recover it while
+// printing values, and the original panic is raised again once rendering has
+// stopped.
+type yieldPanic struct {
+	value any
+}
+
+// lineWriter splits the output of a printer into lines passed to an iterator
+// yield function, stopping the printer when the function returns false.
+type lineWriter struct {
+	p     *Printer
+	yield func(string) bool
+
+	buf []byte
+}
+
+func (w *lineWriter) Write(data []byte) (int, error) {
+	if w.p.stopped {
+		return len(data), nil
+	}
+
+	w.buf = append(w.buf, data...)
+
+	for {
+		eol := bytes.IndexByte(w.buf, '\n')
+		if eol < 0 {
+			break
+		}
+
+		line := string(w.buf[:eol])
+		w.buf = w.buf[eol+1:]
+
+		if !w.callYield(line) {
+			w.p.stopped = true
+			break
+		}
+	}
+
+	return len(data), nil
+}
+
+func (w *lineWriter) finish() {
+	if len(w.buf) > 0 && !w.p.stopped {
+		w.callYield(string(w.buf))
+	}
+}
+
+func (w *lineWriter) callYield(line string) bool {
+	defer func() {
+		if value := recover(); value != nil {
+			panic(yieldPanic{value: value})
+		}
+	}()
+
+	return w.yield(line)
+}
@@ -0,0 +1,19 @@
+package pp
+
+import "testing"
+
+func TestLinesLoopBodyPanic(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithLayout(LayoutExpanded))
+
+	value := make([]int, 10_000)
+
+	defer func() {
+		if value := recover(); value != "boom" {
+			t.Errorf("got panic %v, expected %q", value, "boom")
+		}
+	}()
+
+	for range p.Lines(value) {
+		panic("boom")
+	}
+}
@@ -0,0 +1,61 @@
+package pp
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The key used in logfmt output when the value printed is not a sequence, a
+// map or a structure.
+const logfmtRootKey = "value"
+
+func (fp *flatPrinter) printLogfmtLeaf(path, color, value string) {
+	p := fp.p
+
+	if fp.nbLeaves > 0 {
+		p.printString(" ")
+	}
+	fp.nbLeaves++
+
+	if path == "" {
+		path = logfmtRootKey
+	}
+
+	p.printColoredString(p.theme.FieldName, path)
+	p.printString("=")
+	p.printColoredString(color, logfmtValue(value))
+}
+
+// logfmtKey replaces characters which cannot be part of a logfmt key.
+func logfmtKey(s string) string {
+	if s == "" {
+		return "_"
+	}
+
+	return strings.Map(func(c rune) rune {
+		if c <= ' ' || c == '=' || c == '"' || !unicode.IsPrint(c) {
+			return '_'
+		}
+
+		return c
+	}, s)
+}
+
+// logfmtValue quotes a value if it cannot be printed as is.
+func logfmtValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	quote := strings.ContainsFunc(s, func(c rune) bool {
+		return c <= ' ' || c == '=' || c == '"' || c == '\\' ||
+			!unicode.IsPrint(c)
+	})
+
+	if quote {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,129 @@
+package pp
+
+// Node is a value in the tree built by Build. Nodes are the representation
+// rendered by the JSON, YAML, HTML, flat and logfmt output formats and by
+// Graph, and can be used to write other renderers without reimplementing the
+// traversal of values.
+type Node struct {
+	Kind NodeKind
+
+	// The name of the type of the value, if known
+	Type string
+
+	// Scalar nodes: the kind of scalar and its textual representation. Numbers
+	// are represented in decimal notation.
+	Scalar ScalarKind
+	Value  string
+
+	Elements []*Node     // sequence nodes
+	Entries  []NodeEntry // map nodes, sorted
+	Fields   []NodeField // structure nodes
+
+	// For nodes referenced by reference nodes, an identifier greater than
+	// zero. Reference nodes use it to identify the node they point to, which
+	// is also available as Target.
+	ID     int
+	Target *Node
+}
+
+type NodeKind string
+
+const (
+	NodeScalar NodeKind = "scalar"
+	NodeSeq    NodeKind = "seq"
+	NodeMap    NodeKind = "map"
+	NodeStruct NodeKind = "struct"
+	NodeRef    NodeKind = "ref"
+)
+
+type ScalarKind string
+
+const (
+	ScalarNil    ScalarKind = "nil"
+	ScalarBool   ScalarKind = "bool"
+	ScalarNumber ScalarKind = "number"
+	ScalarString ScalarKind = "string"
+)
+
+type NodeEntry struct {
+	Key   *Node
+	Value *Node
+}
+
+type NodeField struct {
+	Name  string
+	Value *Node
+}
+
+func Build(value any) *Node {
+	return DefaultPrinter.Build(value)
+}
+
+// Build returns the tree representing a value. The tree is built with the
+// same steps as printing: value formatting functions, field filtering and
+// redaction, map key sorting and pointer references. Values referenced from
+// multiple places, including cyclic values, appear once, other occurrences
+// being reference nodes.
+func (p *Printer) Build(value any) *Node {
+	p = p.snapshot(nil)
+
+	p.reset(value)
+	defer p.releaseBuffer()
+
+	root := p.buildNode(reflectValue(value))
+
+	targets := make(map[int]*Node)
+	var refs []*Node
+
+	walkNodes(root, func(n *Node) {
+		if n.Kind == NodeRef {
+			refs = append(refs, n)
+		} else if n.ID > 0 {
+			targets[n.ID] = n
+		}
+	})
+
+	for _, ref := range refs {
+		ref.Target = targets[ref.ID]
+	}
+
+	return root
+}
+
+// walkNodes calls a function for a node and all the nodes it contains.
+// Reference nodes are not followed.
+func walkNodes(n *Node, fn func(*Node)) {
+	fn(n)
+
+	for _, elem := range n.Elements {
+		walkNodes(elem, fn)
+	}
+
+	for _, entry := range n.Entries {
+		walkNodes(entry.Key, fn)
+		walkNodes(entry.Value, fn)
+	}
+
+	for _, field := range n.Fields {
+		walkNodes(field.Value, fn)
+	}
+}
+
+func scalarNode(scalar ScalarKind, value string) *Node {
+	return &Node{Kind: NodeScalar, Scalar: scalar, Value: value}
+}
+
+// nbChildren returns the number of elements, entries or fields of a node.
+func (n *Node) nbChildren() int {
+	return len(n.Elements) + len(n.Entries) + len(n.Fields)
+}
+
+// composite returns whether a node is a sequence, a map or a structure.
+func (n *Node) composite() bool {
+	switch n.Kind {
+	case NodeSeq, NodeMap, NodeStruct:
+		return true
+	}
+
+	return false
+}
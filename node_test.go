@@ -0,0 +1,22 @@
+package pp
+
+import "testing"
+
+func TestBuildReferences(t *testing.T) {
+	v := &testTreeValue{N: 1}
+
+	root := NewPrinter().Build([]*testTreeValue{v, v})
+	if root.Kind != NodeSeq || len(root.Elements) != 2 {
+		t.Fatalf("unexpected root node %#v", root)
+	}
+
+	target, ref := root.Elements[0], root.Elements[1]
+
+	if target.Kind != NodeStruct || target.ID == 0 {
+		t.Errorf("unexpected target node %#v", target)
+	}
+
+	if ref.Kind != NodeRef || ref.ID != target.ID || ref.Target != target {
+		t.Errorf("unexpected reference node %#v", ref)
+	}
+}
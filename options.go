@@ -0,0 +1,396 @@
+package pp
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// Option is a setting applied to a printer, either when creating it with
+// NewPrinter or for a single call. Options can be passed to any function
+// accepting a label or a list of values, e.g.:
+//
+//	pp.Print(v, pp.WithPrintTypes(pp.PrintTypesAlways))
+//
+// In that case, options only affect the call and do not modify the printer.
+type Option func(*Printer)
+
+func NewPrinter(opts ...Option) *Printer {
+	var p Printer
+
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	return &p
+}
+
+// splitOptions separates options from other arguments.
+func splitOptions(args []any) ([]any, []Option) {
+	var opts []Option
+	var otherArgs []any
+
+	for i, arg := range args {
+		if opt, ok := arg.(Option); ok {
+			if opts == nil {
+				otherArgs = append(otherArgs, args[:i]...)
+			}
+
+			opts = append(opts, opt)
+		} else if opts != nil {
+			otherArgs = append(otherArgs, arg)
+		}
+	}
+
+	if opts == nil {
+		return args, nil
+	}
+
+	return otherArgs, opts
+}
+
+// snapshot returns a copy of the printer with a set of options applied. Values
+// are always rendered with a snapshot, so that the printer is only locked
+// while its configuration is copied: goroutines sharing a printer do not wait
+// for each other, and functions called while rendering a value can use the
+// printer.
+func (p *Printer) snapshot(opts []Option) *Printer {
+	p.mu.Lock()
+	p2 := p.clone()
+	p.mu.Unlock()
+
+	for _, opt := range opts {
+		opt(p2)
+	}
+
+	return p2
+}
+
+func WithDefaultOutput(w io.Writer) Option {
+	return func(p *Printer) { p.defaultOutput = w }
+}
+
+func WithFormatValueFunc(fn FormatValueFunc) Option {
+	return func(p *Printer) { p.formatValue = fn }
+}
+
+func WithMaxInlineColumn(column int) Option {
+	return func(p *Printer) { p.maxInlineColumn = column }
+}
+
+func WithIndent(indent string) Option {
+	return func(p *Printer) { p.indent = indent }
+}
+
+func WithLinePrefix(prefix string) Option {
+	return func(p *Printer) { p.linePrefix = prefix }
+}
+
+func WithPrintTypes(types PrintTypes) Option {
+	return func(p *Printer) { p.printTypes = types }
+}
+
+func WithHidePrivateFields(hide bool) Option {
+	return func(p *Printer) { p.hidePrivateFields = hide }
+}
+
+func WithThousandsGroupingMinDigits(n int) Option {
+	return func(p *Printer) { p.thousandsGroupingMinDigits = n }
+}
+
+func WithThousandsSeparator(sep rune) Option {
+	return func(p *Printer) { p.thousandsSeparator = sep }
+}
+
+func WithColors(colors bool) Option {
+	return func(p *Printer) { p.colors = colors }
+}
+
+func WithTheme(theme Theme) Option {
+	return func(p *Printer) { p.theme = theme }
+}
+
+func WithAutoDetect(autoDetect bool) Option {
+	return func(p *Printer) { p.autoDetect = autoDetect }
+}
+
+func WithMaxDepth(depth int) Option {
+	return func(p *Printer) { p.maxDepth = depth }
+}
+
+func WithMaxElements(n int) Option {
+	return func(p *Printer) { p.maxElements = n }
+}
+
+func WithMaxStringLength(n int) Option {
+	return func(p *Printer) { p.maxStringLength = n }
+}
+
+func WithByteSliceMode(mode ByteSliceMode) Option {
+	return func(p *Printer) { p.byteSliceMode = mode }
+}
+
+func WithFormat(format Format) Option {
+	return func(p *Printer) { p.format = format }
+}
+
+// WithRedactPatterns is the option equivalent to SetRedactPatterns. Since
+// options cannot return errors, it panics if a pattern is invalid.
+func WithRedactPatterns(patterns []string) Option {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			panic(fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+		}
+
+		res[i] = re
+	}
+
+	return func(p *Printer) { p.redactPatterns = res }
+}
+
+func WithFieldFilterFunc(fn FieldFilterFunc) Option {
+	return func(p *Printer) { p.fieldFilter = fn }
+}
+
+func WithUseStringer(use bool) Option {
+	return func(p *Printer) { p.useStringer = use }
+}
+
+func WithRawErrors(raw bool) Option {
+	return func(p *Printer) { p.rawErrors = raw }
+}
+
+func WithUseGoStringer(use bool) Option {
+	return func(p *Printer) { p.useGoStringer = use }
+}
+
+func WithShowAddresses(show bool) Option {
+	return func(p *Printer) { p.showAddresses = show }
+}
+
+func WithIntegerBase(base int) Option {
+	return func(p *Printer) { p.integerBase = base }
+}
+
+func WithFieldOrder(order FieldOrder) Option {
+	return func(p *Printer) { p.fieldOrder = order }
+}
+
+func WithFieldCompareFunc(fn FieldCompareFunc) Option {
+	return func(p *Printer) { p.fieldCompare = fn }
+}
+
+func WithOmitZeroFields(omit bool) Option {
+	return func(p *Printer) { p.omitZeroFields = omit }
+}
+
+func WithLayout(layout Layout) Option {
+	return func(p *Printer) { p.layout = layout }
+}
+
+func WithInlinableFunc(fn InlinableFunc) Option {
+	return func(p *Printer) { p.inlinable = fn }
+}
+
+func WithRecoverPanics(recoverPanics bool) Option {
+	return func(p *Printer) { p.propagatePanics = !recoverPanics }
+}
+
+func WithShowLengths(show bool) Option {
+	return func(p *Printer) { p.showLengths = show }
+}
+
+func WithTimeFormat(layout string) Option {
+	return func(p *Printer) { p.timeFormat = layout }
+}
+
+func WithTimeLocation(location *time.Location) Option {
+	return func(p *Printer) { p.timeLocation = location }
+}
+
+func WithShowMonotonicClock(show bool) Option {
+	return func(p *Printer) { p.showMonotonicClock = show }
+}
+
+func WithDurationStyle(style DurationStyle) Option {
+	return func(p *Printer) { p.durationStyle = style }
+}
+
+func WithExpandURLs(expand bool) Option {
+	return func(p *Printer) { p.expandURLs = expand }
+}
+
+func WithDetectUUIDs(detect bool) Option {
+	return func(p *Printer) { p.detectUUIDs = detect }
+}
+
+func WithExpandJSONStrings(expand bool) Option {
+	return func(p *Printer) { p.expandJSONStrings = expand }
+}
+
+func WithUseTextMarshaler(use bool) Option {
+	return func(p *Printer) { p.useTextMarshaler = use }
+}
+
+func WithShowProtobufInternals(show bool) Option {
+	return func(p *Printer) { p.showProtobufInternals = show }
+}
+
+func WithTypeNameStyle(style TypeNameStyle) Option {
+	return func(p *Printer) { p.typeNameStyle = style }
+}
+
+func WithReferenceAnnotations(definition, use string) Option {
+	return func(p *Printer) {
+		p.referenceDefinition = definition
+		p.referenceUse = use
+	}
+}
+
+func WithAnnotateCyclesOnly(cyclesOnly bool) Option {
+	return func(p *Printer) { p.annotateCyclesOnly = cyclesOnly }
+}
+
+func WithShowCaller(show bool) Option {
+	return func(p *Printer) { p.showCaller = show }
+}
+
+func WithShowCallerFunction(show bool) Option {
+	return func(p *Printer) { p.showCallerFunction = show }
+}
+
+func WithShowTimestamp(show bool) Option {
+	return func(p *Printer) { p.showTimestamp = show }
+}
+
+func WithShowGoroutineID(show bool) Option {
+	return func(p *Printer) { p.showGoroutineID = show }
+}
+
+func WithMapKeyCompareFunc(fn MapKeyCompareFunc) Option {
+	return func(p *Printer) { p.mapKeyCompare = fn }
+}
+
+func WithMaxMapEntries(n int) Option {
+	return func(p *Printer) { p.maxMapEntries = n }
+}
+
+func WithMapEntryOrder(order MapEntryOrder) Option {
+	return func(p *Printer) { p.mapEntryOrder = order }
+}
+
+func WithShowRunes(show bool) Option {
+	return func(p *Printer) { p.showRunes = show }
+}
+
+func WithStringStyle(style StringStyle) Option {
+	return func(p *Printer) { p.stringStyle = style }
+}
+
+func WithStringBlocks(blocks bool) Option {
+	return func(p *Printer) { p.stringBlocks = blocks }
+}
+
+func WithBinaryStringFunc(fn BinaryStringFunc) Option {
+	return func(p *Printer) { p.binaryString = fn }
+}
+
+func WithFloatFormat(format byte, precision int) Option {
+	return func(p *Printer) {
+		p.floatFormat = format
+		p.floatPrecision = precision
+	}
+}
+
+func WithNumericSummaryThreshold(n int) Option {
+	return func(p *Printer) { p.numericSummaryThreshold = n }
+}
+
+func WithNumericSequenceStyle(style NumericSequenceStyle) Option {
+	return func(p *Printer) { p.numericSequenceStyle = style }
+}
+
+func WithMatrixGrid(grid bool) Option {
+	return func(p *Printer) { p.matrixGrid = grid }
+}
+
+func WithMatrixIndices(indices bool) Option {
+	return func(p *Printer) { p.matrixIndices = indices }
+}
+
+func WithImagePreviewWidth(width int) Option {
+	return func(p *Printer) { p.imagePreviewWidth = width }
+}
+
+func WithRelativeTimes(relative bool) Option {
+	return func(p *Printer) { p.relativeTimes = relative }
+}
+
+func WithLabelSuffix(suffix string) Option {
+	return func(p *Printer) { p.labelSuffix = suffix }
+}
+
+func WithLinePrefixFunc(fn LinePrefixFunc) Option {
+	return func(p *Printer) { p.linePrefixFunc = fn }
+}
+
+func WithMaxOutputBytes(n int) Option {
+	return func(p *Printer) { p.maxOutputBytes = n }
+}
+
+func WithDeduplicateOutput(deduplicate bool) Option {
+	return func(p *Printer) { p.deduplicateOutput = deduplicate }
+}
+
+func WithIndentGuides(guides bool) Option {
+	return func(p *Printer) { p.indentGuides = guides }
+}
+
+func WithLevelColors(colors []string) Option {
+	return func(p *Printer) { p.levelColors = colors }
+}
+
+func WithAlignNumbers(align bool) Option {
+	return func(p *Printer) { p.alignNumbers = align }
+}
+
+func WithCollapseRepeats(collapse bool) Option {
+	return func(p *Printer) { p.collapseRepeats = collapse }
+}
+
+func WithBaseline(baseline any) Option {
+	return func(p *Printer) { p.baseline = baseline }
+}
+
+func WithSkipTypes(types ...reflect.Type) Option {
+	return func(p *Printer) { p.skipTypes = types }
+}
+
+// WithSkipTypePatterns is the option equivalent to SetSkipTypePatterns. Since
+// options cannot return errors, it panics if a pattern is invalid.
+func WithSkipTypePatterns(patterns ...string) Option {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			panic(fmt.Sprintf("invalid pattern %q: %v", pattern, err))
+		}
+	}
+
+	return func(p *Printer) { p.skipTypePatterns = patterns }
+}
+
+func WithMaxPointerDepth(depth int) Option {
+	return func(p *Printer) { p.maxPointerDepth = depth }
+}
+
+func WithFieldNameSource(source FieldNameSource) Option {
+	return func(p *Printer) { p.fieldNameSource = source }
+}
+
+func WithShowStructTags(show bool) Option {
+	return func(p *Printer) { p.showStructTags = show }
+}
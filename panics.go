@@ -0,0 +1,38 @@
+package pp
+
+import (
+	"fmt"
+)
+
+// Printing a value can trigger panics, either in the reflect package or in
+// methods called by the printer such as String or Error. Unless disabled,
+// these panics are recovered and the value is replaced by an error marker so
+// that debugging code does not crash the program being debugged.
+
+func panicMessage(value any) string {
+	// fmt recovers panics triggered while formatting the value itself
+	return "<error: " + fmt.Sprint(value) + ">"
+}
+
+// outputOffset returns the position of the end of the output, including data
+// which have already been written when streaming.
+func (p *Printer) outputOffset() int {
+	return p.written + len(p.buf)
+}
+
+func (p *Printer) printPanic(value any, offset, level int) {
+	// Discard the partial representation of the value if it has not been
+	// written yet.
+	if start := offset - p.written; start >= 0 && start <= len(p.buf) {
+		p.buf = p.buf[:start]
+
+		if p.measureWidth {
+			p.width = textWidth(p.buf)
+			p.overflow = p.width > p.widthLimit
+		}
+	}
+
+	p.level = level
+
+	p.printColoredString(p.theme.Annotation, panicMessage(value))
+}
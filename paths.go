@@ -0,0 +1,449 @@
+package pp
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Paths select values nested in other values. They are made of a sequence of
+// segments:
+//
+//   - "Name" or ".Name": a structure field, or the value associated with a
+//     string key in a map;
+//   - "[2]": an element of an array or slice, negative indices counting from
+//     the end, or the value associated with a non-string key in a map;
+//   - "[\"key\"]": the value associated with a quoted string key in a map;
+//   - "*" or "[*]": all the fields, elements or map values of a value.
+//
+// Pointers and interface values are followed automatically.
+
+type pathSegmentType int
+
+const (
+	pathSegmentName pathSegmentType = iota
+	pathSegmentIndex
+	pathSegmentQuotedKey
+	pathSegmentWildcard
+)
+
+type pathSegment struct {
+	segmentType pathSegmentType
+	value       string
+}
+
+// pathValue is a value selected by a path, along with the path leading to it
+// where wildcards have been replaced.
+type pathValue struct {
+	path  string
+	value reflect.Value
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+
+	s := path
+	for s != "" {
+		var segment pathSegment
+
+		switch {
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+
+			if len(s) > 1 && s[1] == '"' {
+				quoted, err := strconv.QuotedPrefix(s[1:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid path %q: invalid quoted "+
+						"key", path)
+				}
+
+				key, _ := strconv.Unquote(quoted)
+				segment = pathSegment{pathSegmentQuotedKey, key}
+				end = 1 + len(quoted)
+
+				if end >= len(s) || s[end] != ']' {
+					end = -1
+				}
+			} else if end > 0 {
+				content := strings.TrimSpace(s[1:end])
+				if content == "*" {
+					segment = pathSegment{pathSegmentWildcard, ""}
+				} else {
+					segment = pathSegment{pathSegmentIndex, content}
+				}
+			}
+
+			if end <= 1 {
+				return nil, fmt.Errorf("invalid path %q: invalid brackets",
+					path)
+			}
+
+			s = s[end+1:]
+
+		default:
+			if s[0] == '.' {
+				if len(segments) == 0 {
+					return nil, fmt.Errorf("invalid path %q: unexpected "+
+						"leading dot", path)
+				}
+
+				s = s[1:]
+			} else if len(segments) > 0 {
+				return nil, fmt.Errorf("invalid path %q: missing dot before "+
+					"%q", path, s)
+			}
+
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+
+			name := s[:end]
+			if name == "" {
+				return nil, fmt.Errorf("invalid path %q: empty name", path)
+			}
+
+			if name == "*" {
+				segment = pathSegment{pathSegmentWildcard, ""}
+			} else {
+				segment = pathSegment{pathSegmentName, name}
+			}
+
+			s = s[end:]
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+func wildcardPath(segments []pathSegment) bool {
+	for _, segment := range segments {
+		if segment.segmentType == pathSegmentWildcard {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Get(value any, path string) (any, error) {
+	return DefaultPrinter.Get(value, path)
+}
+
+// Get returns the value selected by a path in another value. If the path
+// contains wildcards, the result is a []any slice containing all the values
+// selected. Fields hidden or redacted by the printer cannot be selected.
+func (p *Printer) Get(value any, path string) (any, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p = p.snapshot(nil)
+
+	values, err := p.selectPath(reflectValue(value), segments)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]any, len(values))
+	for i, pv := range values {
+		result, ok := valueInterface(accessibleValue(pv.value))
+		if !ok {
+			return nil, fmt.Errorf("%s: value cannot be accessed", pv.path)
+		}
+
+		results[i] = result
+	}
+
+	if wildcardPath(segments) {
+		return results, nil
+	}
+
+	return results[0], nil
+}
+
+func PrintPath(value any, path string) error {
+	return DefaultPrinter.PrintPath(value, path)
+}
+
+// PrintPath prints the values selected by a path in another value, each one
+// being labeled with its path.
+func (p *Printer) PrintPath(value any, path string) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	values, err := p.snapshot(nil).selectPath(reflectValue(value), segments)
+	if err != nil {
+		return err
+	}
+
+	for _, pv := range values {
+		if err := p.Print(pv.value, "%s", pv.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Printer) selectPath(v reflect.Value, segments []pathSegment) ([]pathValue, error) {
+	values := []pathValue{{value: v}}
+
+	// Values selected by a wildcard which do not match the rest of the path
+	// are ignored.
+	wildcard := false
+
+	for _, segment := range segments {
+		var nextValues []pathValue
+
+		for _, pv := range values {
+			children, err := p.selectPathSegment(pv, segment)
+			if err != nil {
+				if wildcard {
+					continue
+				}
+
+				return nil, err
+			}
+
+			nextValues = append(nextValues, children...)
+		}
+
+		values = nextValues
+
+		if segment.segmentType == pathSegmentWildcard {
+			wildcard = true
+		}
+	}
+
+	return values, nil
+}
+
+func (p *Printer) selectPathSegment(pv pathValue, segment pathSegment) ([]pathValue, error) {
+	v := pv.value
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, fmt.Errorf("%s: nil value", pathString(pv.path))
+		}
+
+		v = v.Elem()
+	}
+
+	v = accessibleValue(v)
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return p.selectStructFields(pv.path, v, segment)
+
+	case reflect.Slice, reflect.Array:
+		return p.selectElements(pv.path, v, segment)
+
+	case reflect.Map:
+		return p.selectMapValues(pv.path, v, segment)
+	}
+
+	return nil, fmt.Errorf("%s: %s value has no fields or elements",
+		pathString(pv.path), p.valueTypeString(v))
+}
+
+func (p *Printer) selectStructFields(path string, v reflect.Value, segment pathSegment) ([]pathValue, error) {
+	var values []pathValue
+
+	for _, f := range p.structFields(v) {
+		if segment.segmentType != pathSegmentWildcard {
+			if segment.segmentType != pathSegmentName ||
+				(f.Name != segment.value && f.label() != segment.value) {
+				continue
+			}
+		}
+
+		fieldPath := joinPath(path, f.label())
+
+		if f.redaction != "" {
+			if segment.segmentType == pathSegmentWildcard {
+				continue
+			}
+
+			return nil, fmt.Errorf("%s: field is redacted", fieldPath)
+		}
+
+		values = append(values, pathValue{path: fieldPath, value: f.value})
+	}
+
+	if len(values) == 0 && segment.segmentType != pathSegmentWildcard {
+		return nil, fmt.Errorf("%s: no field %q in %s", pathString(path),
+			segment.value, p.valueTypeString(v))
+	}
+
+	return values, nil
+}
+
+func (p *Printer) selectElements(path string, v reflect.Value, segment pathSegment) ([]pathValue, error) {
+	n := v.Len()
+
+	if segment.segmentType == pathSegmentWildcard {
+		values := make([]pathValue, n)
+		for i := range n {
+			values[i] = pathValue{
+				path:  path + "[" + strconv.Itoa(i) + "]",
+				value: v.Index(i),
+			}
+		}
+
+		return values, nil
+	}
+
+	if segment.segmentType != pathSegmentIndex {
+		return nil, fmt.Errorf("%s: %s value cannot be indexed with %q",
+			pathString(path), p.valueTypeString(v), segment.value)
+	}
+
+	i, err := strconv.Atoi(segment.value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid index %q", pathString(path),
+			segment.value)
+	}
+
+	if i < 0 {
+		i += n
+	}
+
+	if i < 0 || i >= n {
+		return nil, fmt.Errorf("%s: index %s out of range (length %d)",
+			pathString(path), segment.value, n)
+	}
+
+	return []pathValue{{
+		path:  path + "[" + strconv.Itoa(i) + "]",
+		value: v.Index(i),
+	}}, nil
+}
+
+func (p *Printer) selectMapValues(path string, v reflect.Value, segment pathSegment) ([]pathValue, error) {
+	if segment.segmentType == pathSegmentWildcard {
+		var values []pathValue
+		for _, key := range p.sortedMapKeys(v) {
+			values = append(values, pathValue{
+				path:  path + "[" + mapKeyPathString(key) + "]",
+				value: v.MapIndex(key),
+			})
+		}
+
+		return values, nil
+	}
+
+	key, err := parseMapKey(segment, v.Type().Key())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pathString(path), err)
+	}
+
+	value := v.MapIndex(key)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("%s: no key %s in map", pathString(path),
+			mapKeyPathString(key))
+	}
+
+	return []pathValue{{
+		path:  path + "[" + mapKeyPathString(key) + "]",
+		value: value,
+	}}, nil
+}
+
+func parseMapKey(segment pathSegment, keyType reflect.Type) (reflect.Value, error) {
+	key := reflect.New(keyType).Elem()
+	s := segment.value
+
+	var err error
+
+	switch keyType.Kind() {
+	case reflect.String:
+		key.SetString(s)
+		return key, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(s, 0, keyType.Bits()); err == nil {
+			key.SetInt(i)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 0, keyType.Bits()); err == nil {
+			key.SetUint(u)
+		}
+
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, keyType.Bits()); err == nil {
+			key.SetFloat(f)
+		}
+
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			key.SetBool(b)
+		}
+
+	default:
+		return key, fmt.Errorf("map keys of type %s cannot be selected",
+			keyType)
+	}
+
+	if err != nil || segment.segmentType == pathSegmentQuotedKey {
+		return key, fmt.Errorf("invalid map key %q for type %s", s, keyType)
+	}
+
+	return key, nil
+}
+
+// mapKeyPathString returns the representation of a map key in a path.
+func mapKeyPathString(key reflect.Value) string {
+	for key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+
+	switch key.Kind() {
+	case reflect.String:
+		return strconv.Quote(key.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(key.Float(), 'g', -1, key.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(key.Bool())
+	}
+
+	if value, ok := valueInterface(key); ok {
+		return fmt.Sprint(value)
+	}
+
+	return "?"
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// pathString returns a path for error messages, the empty path designating
+// the root value.
+func pathString(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+
+	return path
+}
@@ -11,5 +11,17 @@ func Print(value any, label ...any) error {
 }
 
 func PrintTo(w io.Writer, value any, label ...any) error {
-	return DefaultPrinter.PrintTo(w, value)
+	return DefaultPrinter.PrintTo(w, value, label...)
+}
+
+func Println(values ...any) error {
+	return DefaultPrinter.Println(values...)
+}
+
+func PrintlnTo(w io.Writer, values ...any) error {
+	return DefaultPrinter.PrintlnTo(w, values...)
+}
+
+func String(value any, label ...any) string {
+	return DefaultPrinter.String(value, label...)
 }
@@ -1,23 +1,39 @@
 package pp
 
 import (
+	"bufio"
 	"bytes"
+	"cmp"
+	"context"
+	"encoding"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 	"unicode/utf8"
-	"unsafe"
 )
 
 type RawString string
 
 type FormatValueFunc func(reflect.Value) any
 
+type FieldFilterFunc func(reflect.StructField, reflect.Value) bool
+
+type InlinableFunc func(reflect.Value) bool
+
+type LinePrefixFunc func(lineNo int) string
+
 type PrintTypes string
 
 const (
@@ -26,8 +42,101 @@ const (
 	PrintTypesNever   PrintTypes = "never"
 )
 
+type Format string
+
+const (
+	FormatNative Format = "native"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatGo     Format = "go"
+	FormatHTML   Format = "html"
+	FormatFlat   Format = "flat"
+	FormatLogfmt Format = "logfmt"
+)
+
+type FieldOrder string
+
+const (
+	FieldOrderDeclaration  FieldOrder = "declaration"
+	FieldOrderAlphabetical FieldOrder = "alphabetical"
+)
+
+type FieldCompareFunc func(reflect.StructField, reflect.StructField) int
+
+type MapKeyCompareFunc func(reflect.Value, reflect.Value) int
+
+type Layout string
+
+const (
+	LayoutAuto     Layout = "auto"
+	LayoutCompact  Layout = "compact"
+	LayoutExpanded Layout = "expanded"
+)
+
+type DurationStyle string
+
+const (
+	DurationStyleGo        DurationStyle = "go"
+	DurationStyleHumanized DurationStyle = "humanized"
+	DurationStyleSeconds   DurationStyle = "seconds"
+)
+
+type TypeNameStyle string
+
+const (
+	TypeNameStyleShort   TypeNameStyle = "short"
+	TypeNameStylePackage TypeNameStyle = "package"
+	TypeNameStyleFull    TypeNameStyle = "full"
+)
+
+type FieldNameSource string
+
+const (
+	FieldNameSourceGoName  FieldNameSource = "go-name"
+	FieldNameSourceJSONTag FieldNameSource = "json-tag"
+	FieldNameSourceYAMLTag FieldNameSource = "yaml-tag"
+)
+
+type MapEntryOrder string
+
+const (
+	MapEntryOrderKeys             MapEntryOrder = "keys"
+	MapEntryOrderValuesAscending  MapEntryOrder = "values-ascending"
+	MapEntryOrderValuesDescending MapEntryOrder = "values-descending"
+)
+
+type StringStyle string
+
+const (
+	StringStyleQuoteGo        StringStyle = "quote-go"
+	StringStyleBackquote      StringStyle = "backquote"
+	StringStyleRawIfPrintable StringStyle = "raw-if-printable"
+)
+
+type BinaryStringFunc func(string) bool
+
+type NumericSequenceStyle string
+
 const (
-	uintptrSize = unsafe.Sizeof(uintptr(0))
+	NumericSequenceStyleFull      NumericSequenceStyle = "full"
+	NumericSequenceStyleSparkline NumericSequenceStyle = "sparkline"
+	NumericSequenceStyleHistogram NumericSequenceStyle = "histogram"
+)
+
+type ByteSliceMode string
+
+const (
+	ByteSliceModeRaw     ByteSliceMode = "raw"
+	ByteSliceModeHexDump ByteSliceMode = "hexdump"
+	ByteSliceModeString  ByteSliceMode = "string"
+)
+
+const (
+	uintptrSize = 4 << (^uintptr(0) >> 63)
+)
+
+var (
+	errorType = reflect.TypeFor[error]()
 )
 
 var (
@@ -49,72 +158,607 @@ type Printer struct {
 	hidePrivateFields          bool
 	thousandsGroupingMinDigits int
 	thousandsSeparator         rune
-
-	buf    []byte
-	level  int
-	inline bool
+	colors                     bool
+	theme                      Theme
+	autoDetect                 bool
+	maxDepth                   int
+	maxPointerDepth            int
+	maxElements                int
+	maxStringLength            int
+	byteSliceMode              ByteSliceMode
+	format                     Format
+	redactPatterns             []*regexp.Regexp
+	skipTypes                  []reflect.Type
+	skipTypePatterns           []string
+	fieldFilter                FieldFilterFunc
+	formatters                 map[reflect.Type]FormatValueFunc
+	interfaceFormatterTypes    []reflect.Type
+	useStringer                bool
+	rawErrors                  bool
+	useGoStringer              bool
+	showAddresses              bool
+	integerBase                int
+	fieldOrder                 FieldOrder
+	fieldNameSource            FieldNameSource
+	fieldCompare               FieldCompareFunc
+	mapKeyCompare              MapKeyCompareFunc
+	maxMapEntries              int
+	showRunes                  bool
+	stringStyle                StringStyle
+	stringBlocks               bool
+	binaryString               BinaryStringFunc
+	floatFormat                byte
+	floatPrecision             int
+	numericSummaryThreshold    int
+	numericSequenceStyle       NumericSequenceStyle
+	matrixGrid                 bool
+	matrixIndices              bool
+	imagePreviewWidth          int
+	relativeTimes              bool
+	labelSuffix                string
+	linePrefixFunc             LinePrefixFunc
+	maxOutputBytes             int
+	deduplicateOutput          bool
+	indentGuides               bool
+	levelColors                []string
+	alignNumbers               bool
+	collapseRepeats            bool
+	showStructTags             bool
+	baseline                   any
+	mapEntryOrder              MapEntryOrder
+	omitZeroFields             bool
+	layout                     Layout
+	inlinable                  InlinableFunc
+	propagatePanics            bool
+	showLengths                bool
+	timeFormat                 string
+	timeLocation               *time.Location
+	showMonotonicClock         bool
+	durationStyle              DurationStyle
+	expandURLs                 bool
+	detectUUIDs                bool
+	expandJSONStrings          bool
+	useTextMarshaler           bool
+	showProtobufInternals      bool
+	typeNameStyle              TypeNameStyle
+	referenceDefinition        string
+	referenceUse               string
+	annotateCyclesOnly         bool
+	showCaller                 bool
+	showCallerFunction         bool
+	showTimestamp              bool
+	showGoroutineID            bool
+
+	buf     []byte
+	scratch []byte
+	level   int
+	depth   int
+	inline  bool
+
+	// The number of pointers dereferenced to reach the current value
+	pointerDepth int
+
+	// When trying to print a value inline, we stop as soon as the output
+	// becomes wider than the limit instead of rendering the entire value.
+	measureWidth bool
+	widthLimit   int
+	width        int
+	overflow     bool
+
+	out           *bufio.Writer
+	written       int
+	stopped       bool
+	ctx           context.Context
+	rowAlignment  *rowAlignment
+	baselineValue reflect.Value
+	label         []any
+	labelPrefix   string
+	headerPrinted bool
+
+	// The cause of the cancellation of the context if it stopped rendering
+	cancellationCause error
 
 	pointers map[uintptr]*pointerRef
 
+	// Reference numbers of pointers represented in the node tree by the
+	// node of another reference number
+	nodeRefAliases map[int]int
+
+	// The package of the value printed as a Go literal, whose unexported
+	// types can be used in the literal
+	goPackage string
+
+	repeats outputRepeats
+
 	mu sync.Mutex
 }
 
-type pointerRef struct {
-	n       int
-	printed bool
+// When streaming, the output buffer is written to the output writer as soon as
+// it reaches this size.
+const streamingBufferSize = 1024
+
+// The maximum number of nested values traversed. Values nested deeper, e.g.
+// the end of very long linked lists, are replaced by a marker instead of
+// exhausting the stack.
+const maxRecursionDepth = 10_000
+
+// The layout of timestamps printed before labels
+const timestampLayout = "15:04:05.000000"
+
+type pointerRef struct {
+	n       int
+	printed bool
+}
+
+func (p *Printer) SetDefaultOutput(w io.Writer) {
+	p.mu.Lock()
+	p.defaultOutput = w
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetFormatValueFunc(fn FormatValueFunc) {
+	p.mu.Lock()
+	p.formatValue = fn
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMaxInlineColumn(column int) {
+	p.mu.Lock()
+	p.maxInlineColumn = column
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetIndent(indent string) {
+	p.mu.Lock()
+	p.indent = indent
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetLinePrefix(prefix string) {
+	p.mu.Lock()
+	p.linePrefix = prefix
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetPrintTypes(types PrintTypes) {
+	p.mu.Lock()
+	p.printTypes = types
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetHidePrivateFields(hide bool) {
+	p.mu.Lock()
+	p.hidePrivateFields = hide
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetThousandsGroupingMinDigits(n int) {
+	p.mu.Lock()
+	p.thousandsGroupingMinDigits = n
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetThousandsSeparator(sep rune) {
+	p.mu.Lock()
+	p.thousandsSeparator = sep
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetColors(colors bool) {
+	p.mu.Lock()
+	p.colors = colors
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetTheme(theme Theme) {
+	p.mu.Lock()
+	p.theme = theme
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetAutoDetect(autoDetect bool) {
+	p.mu.Lock()
+	p.autoDetect = autoDetect
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMaxDepth(depth int) {
+	p.mu.Lock()
+	p.maxDepth = depth
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMaxPointerDepth(depth int) {
+	p.mu.Lock()
+	p.maxPointerDepth = depth
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMaxElements(n int) {
+	p.mu.Lock()
+	p.maxElements = n
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMaxStringLength(n int) {
+	p.mu.Lock()
+	p.maxStringLength = n
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetByteSliceMode(mode ByteSliceMode) {
+	p.mu.Lock()
+	p.byteSliceMode = mode
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetFormat(format Format) {
+	p.mu.Lock()
+	p.format = format
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetRedactPatterns(patterns []string) error {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+
+		res[i] = re
+	}
+
+	p.mu.Lock()
+	p.redactPatterns = res
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *Printer) SetSkipTypes(types ...reflect.Type) {
+	p.mu.Lock()
+	p.skipTypes = slices.Clone(types)
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetSkipTypePatterns(patterns ...string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	p.mu.Lock()
+	p.skipTypePatterns = slices.Clone(patterns)
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *Printer) SetFieldFilterFunc(fn FieldFilterFunc) {
+	p.mu.Lock()
+	p.fieldFilter = fn
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetUseStringer(use bool) {
+	p.mu.Lock()
+	p.useStringer = use
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetRawErrors(raw bool) {
+	p.mu.Lock()
+	p.rawErrors = raw
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetUseGoStringer(use bool) {
+	p.mu.Lock()
+	p.useGoStringer = use
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowAddresses(show bool) {
+	p.mu.Lock()
+	p.showAddresses = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetIntegerBase(base int) {
+	p.mu.Lock()
+	p.integerBase = base
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetFieldOrder(order FieldOrder) {
+	p.mu.Lock()
+	p.fieldOrder = order
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetFieldNameSource(source FieldNameSource) {
+	p.mu.Lock()
+	p.fieldNameSource = source
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetFieldCompareFunc(fn FieldCompareFunc) {
+	p.mu.Lock()
+	p.fieldCompare = fn
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetOmitZeroFields(omit bool) {
+	p.mu.Lock()
+	p.omitZeroFields = omit
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetLayout(layout Layout) {
+	p.mu.Lock()
+	p.layout = layout
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetInlinableFunc(fn InlinableFunc) {
+	p.mu.Lock()
+	p.inlinable = fn
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetRecoverPanics(recoverPanics bool) {
+	p.mu.Lock()
+	p.propagatePanics = !recoverPanics
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowLengths(show bool) {
+	p.mu.Lock()
+	p.showLengths = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetTimeFormat(layout string) {
+	p.mu.Lock()
+	p.timeFormat = layout
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetTimeLocation(location *time.Location) {
+	p.mu.Lock()
+	p.timeLocation = location
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowMonotonicClock(show bool) {
+	p.mu.Lock()
+	p.showMonotonicClock = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetDurationStyle(style DurationStyle) {
+	p.mu.Lock()
+	p.durationStyle = style
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetExpandURLs(expand bool) {
+	p.mu.Lock()
+	p.expandURLs = expand
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetDetectUUIDs(detect bool) {
+	p.mu.Lock()
+	p.detectUUIDs = detect
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetExpandJSONStrings(expand bool) {
+	p.mu.Lock()
+	p.expandJSONStrings = expand
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetUseTextMarshaler(use bool) {
+	p.mu.Lock()
+	p.useTextMarshaler = use
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowProtobufInternals(show bool) {
+	p.mu.Lock()
+	p.showProtobufInternals = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetTypeNameStyle(style TypeNameStyle) {
+	p.mu.Lock()
+	p.typeNameStyle = style
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetReferenceAnnotations(definition, use string) {
+	p.mu.Lock()
+	p.referenceDefinition = definition
+	p.referenceUse = use
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetAnnotateCyclesOnly(cyclesOnly bool) {
+	p.mu.Lock()
+	p.annotateCyclesOnly = cyclesOnly
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowCaller(show bool) {
+	p.mu.Lock()
+	p.showCaller = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowCallerFunction(show bool) {
+	p.mu.Lock()
+	p.showCallerFunction = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowTimestamp(show bool) {
+	p.mu.Lock()
+	p.showTimestamp = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowGoroutineID(show bool) {
+	p.mu.Lock()
+	p.showGoroutineID = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMapKeyCompareFunc(fn MapKeyCompareFunc) {
+	p.mu.Lock()
+	p.mapKeyCompare = fn
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMaxMapEntries(n int) {
+	p.mu.Lock()
+	p.maxMapEntries = n
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMapEntryOrder(order MapEntryOrder) {
+	p.mu.Lock()
+	p.mapEntryOrder = order
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetShowRunes(show bool) {
+	p.mu.Lock()
+	p.showRunes = show
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetStringStyle(style StringStyle) {
+	p.mu.Lock()
+	p.stringStyle = style
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetStringBlocks(blocks bool) {
+	p.mu.Lock()
+	p.stringBlocks = blocks
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetBinaryStringFunc(fn BinaryStringFunc) {
+	p.mu.Lock()
+	p.binaryString = fn
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetFloatFormat(format byte, precision int) {
+	p.mu.Lock()
+	p.floatFormat = format
+	p.floatPrecision = precision
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetNumericSummaryThreshold(n int) {
+	p.mu.Lock()
+	p.numericSummaryThreshold = n
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetNumericSequenceStyle(style NumericSequenceStyle) {
+	p.mu.Lock()
+	p.numericSequenceStyle = style
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMatrixGrid(grid bool) {
+	p.mu.Lock()
+	p.matrixGrid = grid
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetMatrixIndices(indices bool) {
+	p.mu.Lock()
+	p.matrixIndices = indices
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetImagePreviewWidth(width int) {
+	p.mu.Lock()
+	p.imagePreviewWidth = width
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetRelativeTimes(relative bool) {
+	p.mu.Lock()
+	p.relativeTimes = relative
+	p.mu.Unlock()
+}
+
+func (p *Printer) SetLabelSuffix(suffix string) {
+	p.mu.Lock()
+	p.labelSuffix = suffix
+	p.mu.Unlock()
 }
 
-func (p *Printer) SetDefaultOutput(w io.Writer) {
+func (p *Printer) SetLinePrefixFunc(fn LinePrefixFunc) {
 	p.mu.Lock()
-	p.defaultOutput = w
+	p.linePrefixFunc = fn
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetFormatValueFunc(fn FormatValueFunc) {
+func (p *Printer) SetMaxOutputBytes(n int) {
 	p.mu.Lock()
-	p.formatValue = fn
+	p.maxOutputBytes = n
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetMaxInlineColumn(column int) {
+func (p *Printer) SetDeduplicateOutput(deduplicate bool) {
 	p.mu.Lock()
-	p.maxInlineColumn = column
+	p.deduplicateOutput = deduplicate
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetIndent(indent string) {
+func (p *Printer) SetIndentGuides(guides bool) {
 	p.mu.Lock()
-	p.indent = indent
+	p.indentGuides = guides
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetLinePrefix(prefix string) {
+func (p *Printer) SetLevelColors(colors []string) {
 	p.mu.Lock()
-	p.linePrefix = prefix
+	p.levelColors = slices.Clone(colors)
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetPrintTypes(types PrintTypes) {
+func (p *Printer) SetAlignNumbers(align bool) {
 	p.mu.Lock()
-	p.printTypes = types
+	p.alignNumbers = align
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetHidePrivateFields(hide bool) {
+func (p *Printer) SetCollapseRepeats(collapse bool) {
 	p.mu.Lock()
-	p.hidePrivateFields = hide
+	p.collapseRepeats = collapse
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetThousandsGroupingMinDigits(n int) {
+func (p *Printer) SetShowStructTags(show bool) {
 	p.mu.Lock()
-	p.thousandsGroupingMinDigits = n
+	p.showStructTags = show
 	p.mu.Unlock()
 }
 
-func (p *Printer) SetThousandsSeparator(sep rune) {
+func (p *Printer) SetBaseline(baseline any) {
 	p.mu.Lock()
-	p.thousandsSeparator = sep
+	p.baseline = baseline
 	p.mu.Unlock()
 }
 
@@ -123,24 +767,192 @@ func (p *Printer) Print(value any, label ...any) error {
 }
 
 func (p *Printer) PrintTo(w io.Writer, value any, label ...any) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	label, opts := splitOptions(label)
+	p2 := p.snapshot(opts)
 
-	p.reset(value)
+	p2.reset(value)
+	defer p2.releaseBuffer()
 
 	if w == nil {
-		w = p.defaultOutput
+		w = p2.defaultOutput
+	}
+
+	return p.renderOutput(p2, w, value, label...)
+}
+
+func (p *Printer) Println(values ...any) error {
+	return p.PrintlnTo(nil, values...)
+}
+
+// PrintlnTo prints multiple values, each one in its own block. When there are
+// several values, each block is labeled with the index of the value.
+func (p *Printer) PrintlnTo(w io.Writer, values ...any) error {
+	values, opts := splitOptions(values)
+	p2 := p.snapshot(opts)
+
+	for i, value := range values {
+		var label []any
+		if len(values) > 1 {
+			label = []any{"%d", i}
+		}
+
+		p2.reset(value)
+
+		if w == nil {
+			w = p2.defaultOutput
+		}
+
+		err := p.renderOutput(p2, w, value, label...)
+		p2.releaseBuffer()
+
+		if err != nil {
+			return err
+		}
 	}
 
-	p.printValue(value)
+	return nil
+}
+
+func (p *Printer) String(value any, label ...any) string {
+	data := p.renderValue(nil, value, label...)
+	return string(data[:len(data)-1])
+}
+
+// renderInline writes the representation of a value on a single line, without
+// label or final newline character.
+func (p *Printer) renderInline(w io.Writer, value any, opts ...Option) {
+	p2 := p.snapshot(opts)
+
+	p2.reset(value)
+	defer p2.releaseBuffer()
+
+	p2.inline = true
+	defer func() { p2.inline = false }()
+
+	p2.printDocument(value)
+
+	w.Write(p2.buf)
+}
+
+// renderValue renders a value with a snapshot of the printer, for callers
+// which need the representation of a value instead of writing it.
+func (p *Printer) renderValue(w io.Writer, value any, label ...any) []byte {
+	label, opts := splitOptions(label)
+	p2 := p.snapshot(opts)
+
+	p2.reset(value)
+	defer p2.releaseBuffer()
 
 	var buf bytes.Buffer
-	buf.WriteString(p.formatHeader(label...))
-	buf.Write(p.buf)
-	buf.WriteByte('\n')
+	p2.render(w, &buf, value, label...)
+
+	return buf.Bytes()
+}
+
+// render writes the full representation of a value, including its label and
+// the final newline character, to out. The w writer is the final destination
+// of the output and is used to detect terminals. The printer must be a
+// snapshot and must be reset.
+//
+// The output is streamed: the content of the output buffer is written as soon
+// as it grows large enough, so that printing very large values does not
+// require keeping their entire representation in memory.
+func (p *Printer) render(w, out io.Writer, value any, label ...any) error {
+	if p.autoDetect {
+		colors, maxInlineColumn := p.colors, p.maxInlineColumn
+		defer func() {
+			p.colors = colors
+			p.maxInlineColumn = maxInlineColumn
+		}()
+
+		p.detectTerminal(w)
+	}
+
+	if p.layout == LayoutCompact {
+		p.inline = true
+		defer func() { p.inline = false }()
+	}
+
+	var limit *limitWriter
+	if p.maxOutputBytes > 0 {
+		limit = &limitWriter{w: out, max: p.maxOutputBytes, colors: p.colors}
+		out = limit
+	}
+
+	if p.linePrefixFunc != nil {
+		linePrefix := p.linePrefix
+		defer func() { p.linePrefix = linePrefix }()
+
+		p.linePrefix = ""
+		out = &linePrefixWriter{w: out, fn: p.linePrefixFunc}
+	}
+
+	p.out = getWriter(out)
+	p.label = label
+	p.headerPrinted = false
+	defer func() {
+		putWriter(p.out)
+		p.out = nil
+		p.label = nil
+		p.labelPrefix = ""
+	}()
+
+	p.labelPrefix = p.formatLabelPrefix()
+
+	p.printDocumentContext(value)
+
+	if !p.headerPrinted {
+		p.out.WriteString(p.formatHeader(p.label...))
+	}
+
+	p.out.Write(p.buf)
+	p.out.WriteByte('\n')
+	p.buf = p.buf[:0]
+
+	if err := p.out.Flush(); err != nil {
+		return err
+	}
+
+	if limit != nil {
+		return limit.finish()
+	}
+
+	return nil
+}
 
-	_, err := io.Copy(w, &buf)
-	return err
+// flushOutput writes the content of the output buffer when streaming. The last
+// byte is kept in the buffer so that printers can still remove a final newline
+// character.
+func (p *Printer) flushOutput() {
+	if !p.headerPrinted {
+		// The header depends on whether the value is printed on a single line
+		// or not, so we have to wait for the first line to be complete.
+		eol := bytes.IndexByte(p.buf, '\n')
+		if eol < 0 || eol == len(p.buf)-1 {
+			return
+		}
+
+		p.out.WriteString(p.formatHeader(p.label...))
+		p.headerPrinted = true
+	}
+
+	n := len(p.buf) - 1
+	p.out.Write(p.buf[:n])
+	p.written += n
+	p.buf = append(p.buf[:0], p.buf[n])
+}
+
+// inlineClone returns a copy of the printer used to try printing a value on a
+// single line. Inline rendering never triggers another inline attempt, so a
+// single scratch buffer is enough.
+func (p *Printer) inlineClone() *Printer {
+	p2 := p.clone()
+	p2.buf = p.scratch[:0]
+	p2.inline = true
+	p2.measureWidth = true
+	p2.widthLimit = p.currentMaxInlineColumn()
+
+	return p2
 }
 
 func (p *Printer) clone() *Printer {
@@ -154,11 +966,90 @@ func (p *Printer) clone() *Printer {
 		hidePrivateFields:          p.hidePrivateFields,
 		thousandsGroupingMinDigits: p.thousandsGroupingMinDigits,
 		thousandsSeparator:         p.thousandsSeparator,
+		colors:                     p.colors,
+		theme:                      p.theme,
+		autoDetect:                 p.autoDetect,
+		maxDepth:                   p.maxDepth,
+		maxPointerDepth:            p.maxPointerDepth,
+		maxElements:                p.maxElements,
+		maxStringLength:            p.maxStringLength,
+		byteSliceMode:              p.byteSliceMode,
+		format:                     p.format,
+		redactPatterns:             p.redactPatterns,
+		skipTypes:                  p.skipTypes,
+		skipTypePatterns:           p.skipTypePatterns,
+		fieldFilter:                p.fieldFilter,
+		formatters:                 p.formatters,
+		interfaceFormatterTypes:    p.interfaceFormatterTypes,
+		useStringer:                p.useStringer,
+		rawErrors:                  p.rawErrors,
+		useGoStringer:              p.useGoStringer,
+		showAddresses:              p.showAddresses,
+		integerBase:                p.integerBase,
+		fieldOrder:                 p.fieldOrder,
+		fieldNameSource:            p.fieldNameSource,
+		fieldCompare:               p.fieldCompare,
+		mapKeyCompare:              p.mapKeyCompare,
+		maxMapEntries:              p.maxMapEntries,
+		showRunes:                  p.showRunes,
+		stringStyle:                p.stringStyle,
+		stringBlocks:               p.stringBlocks,
+		binaryString:               p.binaryString,
+		floatFormat:                p.floatFormat,
+		floatPrecision:             p.floatPrecision,
+		numericSummaryThreshold:    p.numericSummaryThreshold,
+		numericSequenceStyle:       p.numericSequenceStyle,
+		matrixGrid:                 p.matrixGrid,
+		matrixIndices:              p.matrixIndices,
+		imagePreviewWidth:          p.imagePreviewWidth,
+		relativeTimes:              p.relativeTimes,
+		labelSuffix:                p.labelSuffix,
+		linePrefixFunc:             p.linePrefixFunc,
+		maxOutputBytes:             p.maxOutputBytes,
+		deduplicateOutput:          p.deduplicateOutput,
+		indentGuides:               p.indentGuides,
+		levelColors:                p.levelColors,
+		alignNumbers:               p.alignNumbers,
+		collapseRepeats:            p.collapseRepeats,
+		showStructTags:             p.showStructTags,
+		baseline:                   p.baseline,
+		mapEntryOrder:              p.mapEntryOrder,
+		omitZeroFields:             p.omitZeroFields,
+		layout:                     p.layout,
+		inlinable:                  p.inlinable,
+		propagatePanics:            p.propagatePanics,
+		showLengths:                p.showLengths,
+		timeFormat:                 p.timeFormat,
+		timeLocation:               p.timeLocation,
+		showMonotonicClock:         p.showMonotonicClock,
+		durationStyle:              p.durationStyle,
+		expandURLs:                 p.expandURLs,
+		detectUUIDs:                p.detectUUIDs,
+		expandJSONStrings:          p.expandJSONStrings,
+		useTextMarshaler:           p.useTextMarshaler,
+		showProtobufInternals:      p.showProtobufInternals,
+		typeNameStyle:              p.typeNameStyle,
+		referenceDefinition:        p.referenceDefinition,
+		referenceUse:               p.referenceUse,
+		annotateCyclesOnly:         p.annotateCyclesOnly,
+		showCaller:                 p.showCaller,
+		showCallerFunction:         p.showCallerFunction,
+		showTimestamp:              p.showTimestamp,
+		showGoroutineID:            p.showGoroutineID,
 
 		level:  p.level,
+		depth:  p.depth,
 		inline: p.inline,
+		ctx:    p.ctx,
+
+		pointerDepth: p.pointerDepth,
 
-		pointers: p.pointers,
+		rowAlignment:  p.rowAlignment,
+		baselineValue: p.baselineValue,
+
+		pointers:       p.pointers,
+		nodeRefAliases: p.nodeRefAliases,
+		goPackage:      p.goPackage,
 	}
 
 	return &p2
@@ -185,6 +1076,59 @@ func (p *Printer) reset(value any) {
 		p.printTypes = PrintTypesDefault
 	}
 
+	if p.byteSliceMode == "" {
+		p.byteSliceMode = ByteSliceModeRaw
+	}
+
+	if p.format == "" {
+		p.format = FormatNative
+	}
+
+	if p.fieldOrder == "" {
+		p.fieldOrder = FieldOrderDeclaration
+	}
+
+	if p.fieldNameSource == "" {
+		p.fieldNameSource = FieldNameSourceGoName
+	}
+
+	if p.layout == "" {
+		p.layout = LayoutAuto
+	}
+
+	if p.durationStyle == "" {
+		p.durationStyle = DurationStyleGo
+	}
+
+	if p.numericSequenceStyle == "" {
+		p.numericSequenceStyle = NumericSequenceStyleFull
+	}
+
+	if p.floatFormat == 0 {
+		p.floatFormat = 'f'
+		p.floatPrecision = -1
+	}
+
+	if p.stringStyle == "" {
+		p.stringStyle = StringStyleQuoteGo
+	}
+
+	if p.mapEntryOrder == "" {
+		p.mapEntryOrder = MapEntryOrderKeys
+	}
+
+	if p.typeNameStyle == "" {
+		p.typeNameStyle = TypeNameStylePackage
+	}
+
+	if p.referenceDefinition == "" {
+		p.referenceDefinition = "#%d="
+	}
+
+	if p.referenceUse == "" {
+		p.referenceUse = "#%d#"
+	}
+
 	if p.thousandsGroupingMinDigits == 0 {
 		p.thousandsGroupingMinDigits = DefaultThousandsGroupingMinDigits
 	}
@@ -193,24 +1137,48 @@ func (p *Printer) reset(value any) {
 		p.thousandsSeparator = DefaultThousandsSeparator
 	}
 
-	p.buf = nil
+	if p.theme == (Theme{}) {
+		p.theme = DefaultTheme
+	}
+
+	p.buf = getBuffer()
+	p.scratch = getBuffer()
+	p.written = 0
+	p.baselineValue = reflectValue(p.baseline)
 
 	if value != nil {
-		p.initPointers(reflect.ValueOf(value))
+		p.initPointers(reflectValue(value))
 	}
 }
 
+func (p *Printer) releaseBuffer() {
+	putBuffer(p.buf)
+	putBuffer(p.scratch)
+	p.buf = nil
+	p.scratch = nil
+}
+
 func (p *Printer) initPointers(v reflect.Value) {
 	p.pointers = make(map[uintptr]*pointerRef)
+	p.nodeRefAliases = make(map[int]int)
 
 	visitedPointers := make(map[uintptr]struct{})
 
+	// Pointers currently being visited; finding one of them again means
+	// that we are in a cycle.
+	activePointers := make(map[uintptr]struct{})
+
+	depth := 0
+
 	var fn func(reflect.Value)
 	fn = func(v reflect.Value) {
-		if v.IsZero() {
+		if !v.IsValid() || v.IsZero() || depth >= maxRecursionDepth || p.contextDone() {
 			return
 		}
 
+		depth++
+		defer func() { depth-- }()
+
 		switch v.Kind() {
 		case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
 		case reflect.Pointer, reflect.Interface:
@@ -228,11 +1196,20 @@ func (p *Printer) initPointers(v reflect.Value) {
 			ptr := v.Pointer()
 
 			if _, found := visitedPointers[ptr]; found {
-				p.pointers[ptr] = &pointerRef{n: len(p.pointers) + 1}
+				_, active := activePointers[ptr]
+				if !p.annotateCyclesOnly || active {
+					if _, found := p.pointers[ptr]; !found {
+						p.pointers[ptr] = &pointerRef{n: len(p.pointers) + 1}
+					}
+				}
+
 				return
 			}
 
 			visitedPointers[ptr] = struct{}{}
+
+			activePointers[ptr] = struct{}{}
+			defer delete(activePointers, ptr)
 		}
 
 		switch v.Kind() {
@@ -249,130 +1226,448 @@ func (p *Printer) initPointers(v reflect.Value) {
 			}
 
 		case reflect.Struct:
-			for i := range v.NumField() {
-				fn(v.Field(i))
+			if c, ok := collectionContent(v); ok {
+				// The content is a temporary value whose address does not
+				// mean anything, so we only visit its elements.
+				if c.Kind() == reflect.Map {
+					iter := c.MapRange()
+					for iter.Next() {
+						fn(iter.Key())
+						fn(iter.Value())
+					}
+				} else {
+					for i := range c.Len() {
+						fn(c.Index(i))
+					}
+				}
+
+				break
+			}
+
+			for _, f := range p.structFields(v) {
+				if f.redaction == "" {
+					fn(f.value)
+				}
+			}
+
+		case reflect.Pointer:
+			fn(v.Elem())
+
+		case reflect.Interface:
+			fn(v.Elem())
+		}
+	}
+
+	fn(v)
+}
+
+func (p *Printer) pointerAnnotation(ptr uintptr) (bool, string) {
+	first, n := p.pointerReference(ptr)
+	if n == 0 {
+		return false, ""
+	}
+
+	if first {
+		return true, fmt.Sprintf(p.referenceDefinition, n)
+	}
+
+	return false, fmt.Sprintf(p.referenceUse, n)
+}
+
+// savePointerReferences returns a function restoring the state of pointer
+// references, used to cancel speculative printing.
+func (p *Printer) savePointerReferences() func() {
+	var printed []*pointerRef
+	for _, ref := range p.pointers {
+		if ref.printed {
+			printed = append(printed, ref)
+		}
+	}
+
+	return func() {
+		for _, ref := range p.pointers {
+			ref.printed = false
+		}
+
+		for _, ref := range printed {
+			ref.printed = true
+		}
+	}
+}
+
+func (p *Printer) pointerReference(ptr uintptr) (bool, int) {
+	ref, found := p.pointers[ptr]
+	if !found {
+		return false, 0
+	}
+
+	if !ref.printed {
+		ref.printed = true
+		return true, ref.n
+	}
+
+	return false, ref.n
+}
+
+func (p *Printer) currentMaxInlineColumn() int {
+	return p.maxInlineColumn - len(p.linePrefix) - p.level*len(p.indent)
+}
+
+func (p *Printer) formatHeader(label ...any) string {
+	style, label := splitLabelStyle(label)
+	if style == "" {
+		style = p.theme.Label
+	}
+
+	labelString := p.labelString(label...)
+	if labelString == "" {
+		return p.linePrefix
+	}
+
+	lines := strings.Split(labelString, "\n")
+	for i, line := range lines {
+		if p.colors && style != "" {
+			line = "\x1b[" + style + "m" + line + "\x1b[0m"
+		}
+
+		lines[i] = p.linePrefix + line
+	}
+
+	header := strings.Join(lines, "\n")
+
+	// Values are printed after the label if both fit on a single line
+	eol := bytes.IndexByte(p.buf, '\n')
+	if len(lines) > 1 || (eol >= 0 && eol < len(p.buf)-1) {
+		return header + "\n" + p.linePrefix
+	} else {
+		return header + " "
+	}
+}
+
+// formatLabelPrefix returns the information printed before the label of a
+// value: timestamp, goroutine identifier and location of the caller.
+func (p *Printer) formatLabelPrefix() string {
+	var parts []string
+
+	if p.showTimestamp {
+		parts = append(parts, "["+time.Now().Format(timestampLayout)+"]")
+	}
+
+	if p.showGoroutineID {
+		parts = append(parts, "[goroutine "+goroutineID()+"]")
+	}
+
+	if p.showCaller {
+		parts = append(parts, "["+callerLocation(p.showCallerFunction)+"]")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// labelString returns the label of a value with its prefix.
+func (p *Printer) labelString(label ...any) string {
+	_, label = splitLabelStyle(label)
+
+	s := p.labelPrefix
+
+	if len(label) > 0 {
+		format, ok := label[0].(string)
+		if !ok {
+			panic("label format is not a string")
+		}
+
+		if s != "" {
+			s += " "
+		}
+
+		text := fmt.Sprintf(format, label[1:]...)
+		if p.labelSuffix != "" {
+			s += text + p.labelSuffix
+		} else {
+			s += "[" + text + "]"
+		}
+	}
+
+	return s
+}
+
+// LabelStyle is a SGR parameter string which can be passed as first label
+// argument to printing functions to style the label when colors are enabled,
+// e.g. pp.Print(value, pp.LabelStyle("1;4"), "request %d", id).
+type LabelStyle string
+
+func splitLabelStyle(label []any) (string, []any) {
+	if len(label) > 0 {
+		if style, ok := label[0].(LabelStyle); ok {
+			return string(style), label[1:]
+		}
+	}
+
+	return "", label
+}
+
+func (p *Printer) printDocument(value any) {
+	switch p.format {
+	case FormatJSON:
+		p.printJSONNode(p.buildNode(reflectValue(value)))
+	case FormatYAML:
+		if p.inline {
+			// JSON is valid YAML flow syntax
+			p.printJSONNode(p.buildNode(reflectValue(value)))
+		} else {
+			p.printYAMLDocument(p.buildNode(reflectValue(value)))
+		}
+	case FormatGo:
+		p.printGoDocument(reflectValue(value))
+	case FormatHTML:
+		p.printHTMLDocument(p.buildNode(reflectValue(value)))
+	case FormatFlat:
+		p.printFlatDocument(p.buildNode(reflectValue(value)))
+	case FormatLogfmt:
+		p.printLogfmtDocument(p.buildNode(reflectValue(value)))
+	default:
+		p.printValue(reflectValue(value))
+	}
+}
+
+func (p *Printer) printValueLine(value any) {
+	p.printLineStart()
+	p.printValue(reflectValue(value))
+	p.printNewline()
+}
+
+func (p *Printer) printValue(v reflect.Value) {
+	if p.overflow || p.stopped {
+		return
+	}
+
+	p.checkContext()
+
+	if p.depth >= maxRecursionDepth {
+		p.printColoredString(p.theme.Annotation, "<max depth exceeded>")
+		return
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+
+	v = accessibleValue(v)
+
+	// Invalid values come from nil interfaces, e.g. Print(nil)
+	if !v.IsValid() {
+		p.printColoredString(p.theme.Keyword, "nil")
+		return
+	}
+
+	if p.skippedValue(v) {
+		p.printColoredString(p.theme.Annotation, p.skippedValueString(v))
+		return
+	}
+
+	if !p.propagatePanics {
+		offset, level := p.outputOffset(), p.level
+		defer func() {
+			if value := recover(); value != nil {
+				if renderAborted(value) {
+					panic(value)
+				}
+
+				p.printPanic(value, offset, level)
 			}
+		}()
+	}
+
+	// In the default mode, the content of interface values is printed with
+	// its dynamic type instead of the type of the interface.
+	var dynamicType bool
+	if p.printTypes == PrintTypesDefault &&
+		v.Kind() == reflect.Interface && !v.IsNil() {
+		v = accessibleValue(v.Elem())
+		dynamicType = !p.typeVisible(v)
+
+		if p.skippedValue(v) {
+			p.printColoredString(p.theme.Annotation, p.skippedValueString(v))
+			return
+		}
+	}
+
+	inlinable := p.layout != LayoutExpanded && p.inlinableValue(v)
+	if inlinable && !p.inline {
+		// The inline rendering is discarded if the value does not fit on a
+		// single line: pointers it printed must be printed again.
+		restorePointerReferences := p.savePointerReferences()
+
+		p2 := p.inlineClone()
+		p2.printValueContent(v, dynamicType)
+		p.scratch = p2.buf
+		p.inline = false
+
+		if !p2.overflow {
+			p.printBytes(p.scratch)
+			return
+		}
+
+		restorePointerReferences()
+	}
+
+	p.printValueContent(v, dynamicType)
+}
+
+func (p *Printer) printValueContent(v reflect.Value, dynamicType bool) {
+	printType := dynamicType || p.printTypeForValue(v)
+
+	v, rawString, formatted := p.formatValueChain(v)
+	if rawString != nil {
+		if p.printTypes != PrintTypesNever {
+			p.printColoredString(p.theme.Type, p.valueTypeString(v))
+			p.printByte('(')
+		}
 
-		case reflect.Pointer:
-			fn(v.Elem())
+		p.printValueString(v, string(*rawString))
 
-		case reflect.Interface:
-			fn(v.Elem())
+		if p.printTypes != PrintTypesNever {
+			p.printByte(')')
 		}
+		return
 	}
 
-	fn(v)
-}
+	if formatted {
+		printType = true
+	} else if s, ok := p.goStringerValue(v); ok {
+		p.printString(s.GoString())
+		return
+	} else if err, ok := p.errorValue(v); ok {
+		p.printErrorValue(err)
+		return
+	}
 
-func (p *Printer) pointerAnnotation(ptr uintptr) (bool, string) {
-	ref, found := p.pointers[ptr]
-	if !found {
-		return false, ""
+	if printType {
+		p.printColoredString(p.theme.Type, p.valueTypeString(v))
+		p.printByte('(')
 	}
 
-	if !ref.printed {
-		ref.printed = true
-		return true, "#" + strconv.Itoa(ref.n) + "="
+	if p.elidedValue(v) {
+		p.printElidedValue(v)
+	} else if n := p.jsonValueNode(v); n != nil {
+		p.printJSONNode(n)
+	} else if c, ok := collectionContent(v); ok {
+		p.printValueByKind(c)
+	} else {
+		p.printValueByKind(v)
 	}
 
-	return false, "#" + strconv.Itoa(ref.n) + "#"
-}
+	if printType {
+		p.printByte(')')
+	}
 
-func (p *Printer) currentMaxInlineColumn() int {
-	return p.maxInlineColumn - len(p.linePrefix) - p.level*len(p.indent)
+	if v.Kind() == reflect.Chan && !v.IsNil() {
+		p.printColoredString(p.theme.Annotation,
+			"@"+formatPointerAddress(v.Pointer()))
+	}
 }
 
-func (p *Printer) formatHeader(label ...any) string {
-	if len(label) == 0 {
-		return p.linePrefix
-	}
+// formatValueChain applies the formatting function to a value. Formatting
+// functions can return values which are themselves formattable, so we iterate
+// until we get to a value we cannot format. If formatting ends with a raw
+// string, it is returned along with the value it represents.
+func (p *Printer) formatValueChain(v reflect.Value) (reflect.Value, *RawString, bool) {
+	var formatted bool
+
+	for v.Kind() != 0 {
+		var vs any
+		if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if !v.IsNil() {
+				vs = p.callFormatValue(v.Elem())
+			}
+		} else {
+			vs = p.callFormatValue(v)
+		}
 
-	format, ok := label[0].(string)
-	if !ok {
-		panic("label format is not a string")
-	}
+		if vs == nil {
+			break
+		}
 
-	labelString := fmt.Sprintf("["+format+"]", label[1:]...)
+		if s, ok := vs.(RawString); ok {
+			return v, &s, true
+		}
 
-	if eol := bytes.IndexByte(p.buf, '\n'); eol >= 0 && eol < len(p.buf)-1 {
-		return p.linePrefix + labelString + "\n" + p.linePrefix
-	} else {
-		return p.linePrefix + labelString + " "
+		v = reflect.ValueOf(vs)
+		formatted = true
 	}
-}
 
-func (p *Printer) printValueLine(value any) {
-	p.printLineStart()
-	p.printValue(value)
-	p.printNewline()
+	return v, nil, formatted
 }
 
-func (p *Printer) printValue(value any) {
-	var v reflect.Value
-	if rv, ok := value.(reflect.Value); ok {
-		v = rv
-	} else {
-		v = reflect.ValueOf(value)
+// callFormatValue calls the formatter registered for the type of a value if
+// there is one, and the formatting function otherwise.
+func (p *Printer) callFormatValue(v reflect.Value) any {
+	if fn := p.lookupFormatter(v.Type()); fn != nil {
+		if vs := fn(v); vs != nil {
+			return vs
+		}
 	}
 
-	inlinable := p.inlinableValue(v)
-	if inlinable && !p.inline {
-		p2 := p.clone()
+	if vs := p.formatTimeValue(v); vs != nil {
+		return vs
+	}
 
-		p2.inline = true
-		p2.printValue(v)
-		data := p2.buf
-		p.inline = false
+	if vs := p.formatDurationValue(v); vs != nil {
+		return vs
+	}
 
-		if utf8.RuneCount(data) <= p.currentMaxInlineColumn() {
-			p.printBytes(data)
-			return
-		}
+	if vs := p.formatURLValue(v); vs != nil {
+		return vs
+	}
+
+	if vs := p.formatUUIDValue(v); vs != nil {
+		return vs
 	}
 
-	printType := p.printTypeForValue(v)
+	if vs := p.formatImageValue(v); vs != nil {
+		return vs
+	}
 
-	// Formatting function can return values which are themselves formattable.
-	// So we iterate until we get to a value we cannot format.
 	if p.formatValue != nil {
-		for v.Kind() != 0 {
-			var vs any
-			if v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
-				if !v.IsNil() {
-					vs = p.formatValue(v.Elem())
-				}
-			} else {
-				vs = p.formatValue(v)
-			}
+		if vs := p.formatValue(v); vs != nil {
+			return vs
+		}
+	}
 
-			if vs == nil {
-				break
-			}
+	if p.useStringer {
+		if s, ok := valueAs[fmt.Stringer](v); ok {
+			return RawString(s.String())
+		}
+	}
 
-			if s, ok := vs.(RawString); ok {
-				if p.printTypes != PrintTypesNever {
-					p.printString(p.valueTypeString(v))
-					p.printByte('(')
-				}
+	if p.useTextMarshaler {
+		if m, ok := valueAs[encoding.TextMarshaler](v); ok {
+			if text, err := m.MarshalText(); err == nil {
+				return RawString(text)
+			}
+		}
+	}
 
-				p.printValueString(v, string(s))
+	return nil
+}
 
-				if p.printTypes != PrintTypesNever {
-					p.printByte(')')
-				}
-				return
-			}
+func (p *Printer) goStringerValue(v reflect.Value) (fmt.GoStringer, bool) {
+	if !p.useGoStringer {
+		return nil, false
+	}
 
-			v = reflect.ValueOf(vs)
-			printType = true
+	switch v.Kind() {
+	case 0:
+		return nil, false
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
 		}
 	}
 
-	if printType {
-		p.printString(p.valueTypeString(v))
-		p.printByte('(')
-	}
+	return valueAs[fmt.GoStringer](v)
+}
 
+func (p *Printer) printValueByKind(v reflect.Value) {
 	switch v.Kind() {
 	case reflect.Bool:
 		p.printBooleanValue(v)
@@ -396,8 +1691,14 @@ func (p *Printer) printValue(value any) {
 		p.printComplexValue(v, 128)
 	case reflect.String:
 		p.printStringValue(v)
-	case reflect.Array, reflect.Slice:
+	case reflect.Array:
 		p.printSequenceValue(v)
+	case reflect.Slice:
+		if p.byteSliceMode != ByteSliceModeRaw && isByteSlice(v) {
+			p.printByteSliceValue(v)
+		} else {
+			p.printSequenceValue(v)
+		}
 	case reflect.Map:
 		p.printMapValue(v)
 	case reflect.Struct:
@@ -415,17 +1716,40 @@ func (p *Printer) printValue(value any) {
 	default:
 		p.printUnknownValue(v)
 	}
+}
 
-	if printType {
-		p.printByte(')')
+func reflectValue(value any) reflect.Value {
+	if v, ok := value.(reflect.Value); ok {
+		return v
 	}
+
+	return reflect.ValueOf(value)
 }
 
 func (p *Printer) printLineStart() {
 	p.printString(p.linePrefix)
 
+	guides := p.indentGuides && p.format == FormatNative
+
 	for range p.level {
+		if guides {
+			p.printIndentGuide()
+		} else {
+			p.printString(p.indent)
+		}
+	}
+}
+
+// printIndentGuide prints an indentation level starting with a vertical line.
+// The line replaces the first character of the indentation string unless it
+// is a tabulation, so that the width of the indentation does not change.
+func (p *Printer) printIndentGuide() {
+	p.printColoredString(p.theme.Annotation, "│")
+
+	if strings.HasPrefix(p.indent, "\t") {
 		p.printString(p.indent)
+	} else {
+		p.printString(p.indent[1:])
 	}
 }
 
@@ -434,69 +1758,150 @@ func (p *Printer) printNewline() {
 }
 
 func (p *Printer) printByte(c byte) {
+	if p.measureWidth && !p.addWidth(1) {
+		return
+	}
+
 	p.buf = append(p.buf, c)
+	p.streamOutput()
 }
 
 func (p *Printer) printBytes(data []byte) {
+	if p.measureWidth && !p.addWidth(textWidth(data)) {
+		return
+	}
+
 	p.buf = append(p.buf, data...)
+	p.streamOutput()
 }
 
 func (p *Printer) printString(s string) {
-	p.printBytes([]byte(s))
+	if p.measureWidth && !p.addWidth(utf8.RuneCountInString(s)) {
+		return
+	}
+
+	p.buf = append(p.buf, s...)
+	p.streamOutput()
 }
 
 func (p *Printer) printFormat(format string, args ...any) {
 	p.printString(fmt.Sprintf(format, args...))
 }
 
+func (p *Printer) addWidth(n int) bool {
+	if p.overflow {
+		return false
+	}
+
+	p.width += n
+	if p.width > p.widthLimit {
+		p.overflow = true
+		return false
+	}
+
+	return true
+}
+
+func (p *Printer) streamOutput() {
+	if p.out != nil && len(p.buf) >= streamingBufferSize {
+		p.flushOutput()
+	}
+}
+
 func (p *Printer) printBooleanValue(v reflect.Value) {
 	if b := v.Bool(); b {
-		p.printString("true")
+		p.printColoredString(p.theme.Keyword, "true")
 	} else {
-		p.printString("false")
+		p.printColoredString(p.theme.Keyword, "false")
 	}
 }
 
 func (p *Printer) printIntegerValue(v reflect.Value) {
 	i := v.Int()
-	s := strconv.FormatInt(i, 10)
 
-	if p.thousandsSeparator == 0 || len(s) < p.thousandsGroupingMinDigits {
-		p.printString(s)
+	if p.showRunes && v.Kind() == reflect.Int32 && unicode.IsPrint(rune(i)) {
+		p.printColoredString(p.theme.String, strconv.QuoteRune(rune(i)))
+		p.printString(" (")
+		p.printColoredString(p.theme.Number, p.formatInteger(uint64(i)))
+		p.printByte(')')
+		return
+	}
+
+	if i < 0 {
+		p.printColoredString(p.theme.Number, "-"+p.formatInteger(uint64(-i)))
 	} else {
-		p.printString(p.addThousandsSeparator(s))
+		p.printColoredString(p.theme.Number, p.formatInteger(uint64(i)))
 	}
 }
 
 func (p *Printer) printUnsignedIntegerValue(v reflect.Value) {
-	u := v.Uint()
-	s := strconv.FormatUint(u, 10)
+	if p.showRunes && v.Kind() == reflect.Uint8 {
+		b := v.Uint()
 
-	if p.thousandsSeparator == 0 || len(s) < p.thousandsGroupingMinDigits {
-		p.printString(s)
-	} else {
-		p.printString(p.addThousandsSeparator(s))
+		p.printColoredString(p.theme.Number, fmt.Sprintf("0x%02x", b))
+		if b < utf8.RuneSelf {
+			p.printByte(' ')
+			p.printColoredString(p.theme.String, strconv.QuoteRune(rune(b)))
+		}
+
+		return
+	}
+
+	p.printColoredString(p.theme.Number, p.formatInteger(v.Uint()))
+}
+
+func (p *Printer) formatInteger(u uint64) string {
+	base := p.integerBase
+	if base < 2 || base > 36 {
+		base = 10
+	}
+
+	s := strconv.FormatUint(u, base)
+
+	var prefix string
+	groupSize := 3
+
+	switch base {
+	case 2:
+		prefix = "0b"
+		groupSize = 4
+	case 8:
+		prefix = "0o"
+	case 16:
+		prefix = "0x"
+		groupSize = 4
+	}
+
+	if p.thousandsSeparator != 0 && len(s) >= p.thousandsGroupingMinDigits {
+		s = p.groupDigits(s, groupSize)
 	}
+
+	return prefix + s
 }
 
 func (p *Printer) printFloatValue(v reflect.Value, bitSize int) {
-	f := v.Float()
-	s := strconv.FormatFloat(f, 'f', -1, bitSize)
+	p.printColoredString(p.theme.Number, p.formatFloat(v.Float(), bitSize))
+}
+
+func (p *Printer) formatFloat(f float64, bitSize int) string {
+	s := strconv.FormatFloat(f, p.floatFormat, p.floatPrecision, bitSize)
+
+	// Only the integer part of the mantissa is grouped
+	m, exp := s, ""
+	if i := strings.IndexAny(s, "eEp"); i >= 0 {
+		m, exp = s[:i], s[i:]
+	}
 
-	is, fs, found := strings.Cut(s, ".")
+	is, fs, found := strings.Cut(m, ".")
 	if found {
-		if p.thousandsSeparator == 0 || len(s) < p.thousandsGroupingMinDigits {
-			p.printString(is)
-		} else {
-			p.printString(p.addThousandsSeparator(is))
+		if p.thousandsSeparator != 0 && len(s) >= p.thousandsGroupingMinDigits {
+			is = p.addThousandsSeparator(is)
 		}
 
-		p.printByte('.')
-
-		p.printString(fs)
-	} else {
-		p.printString(s)
+		s = is + "." + fs + exp
 	}
+
+	return s
 }
 
 func (p *Printer) printComplexValue(v reflect.Value, bitSize int) {
@@ -504,103 +1909,392 @@ func (p *Printer) printComplexValue(v reflect.Value, bitSize int) {
 
 	bitSize /= 2 // complex64 uses float32 internally, complex128 uses float64
 
-	rs := strconv.FormatFloat(real(c), 'f', -1, bitSize)
-	p.printString(rs)
+	s := strconv.FormatFloat(real(c), p.floatFormat, p.floatPrecision, bitSize)
 
-	is := strconv.FormatFloat(imag(c), 'f', -1, bitSize)
+	is := strconv.FormatFloat(imag(c), p.floatFormat, p.floatPrecision, bitSize)
 	if is[0] != '+' && is[0] != '-' {
-		p.printByte('+')
+		s += "+"
 	}
-	p.printString(is)
-	p.printByte('i')
+	s += is + "i"
+
+	p.printColoredString(p.theme.Number, s)
 }
 
 func (p *Printer) printStringValue(v reflect.Value) {
 	s := v.String()
-	buf := strconv.AppendQuote([]byte{}, s)
-	p.printBytes(buf)
+
+	if p.binaryString != nil && p.binaryString(s) {
+		p.printBinaryString(s)
+		return
+	}
+
+	// The length reported for truncated strings is a number of runes, the unit
+	// used for the maximum length.
+	var length int
+	if p.maxStringLength > 0 {
+		if n := utf8.RuneCountInString(s); n > p.maxStringLength {
+			s = truncateString(s, p.maxStringLength)
+			length = n
+		}
+	}
+
+	multiline := strings.IndexByte(s, '\n') >= 0
+
+	switch {
+	case p.stringBlocks && multiline && !p.inline:
+		p.printStringBlock(s)
+
+	case p.stringBlocks && multiline && p.measureWidth:
+		// String blocks cannot be part of an inline value
+		p.overflow = true
+		return
+
+	case p.stringStyle == StringStyleBackquote && canBackquote(s):
+		if p.inline && multiline {
+			if p.measureWidth {
+				// Multi-line strings cannot be part of an inline value
+				p.overflow = true
+				return
+			}
+
+			p.printColoredBytes(p.theme.String, strconv.AppendQuote(nil, s))
+			break
+		}
+
+		p.printColoredString(p.theme.String, "`"+s+"`")
+
+	case p.stringStyle == StringStyleRawIfPrintable && printableString(s):
+		p.printColoredString(p.theme.String, s)
+
+	default:
+		p.printColoredBytes(p.theme.String, strconv.AppendQuote(nil, s))
+	}
+
+	if length > 0 {
+		p.printColoredString(p.theme.Annotation,
+			"… (len="+strconv.Itoa(length)+")")
+	}
+}
+
+func (p *Printer) printBinaryString(s string) {
+	data := []byte(s)
+	if p.maxStringLength > 0 && len(data) > p.maxStringLength {
+		data = data[:p.maxStringLength]
+	}
+
+	p.printColoredString(p.theme.Annotation, "(len="+strconv.Itoa(len(s))+")")
+	p.printHexDump(data, len(s))
+}
+
+// printStringBlock prints a multi-line string as a block delimited by triple
+// quotes, each line being indented one level deeper than the opening marker.
+// As with Java text blocks, the closing marker is on its own line if the
+// string ends with a newline character, and directly follows the last line
+// otherwise.
+func (p *Printer) printStringBlock(s string) {
+	p.printColoredString(p.theme.String, `"""`)
+	p.printNewline()
+	p.level++
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		last := i == len(lines)-1
+		if last && line == "" {
+			break
+		}
+
+		p.printLineStart()
+
+		if !printableString(strings.ReplaceAll(line, "\t", " ")) && line != "" {
+			quoted := strconv.Quote(line)
+			line = quoted[1 : len(quoted)-1]
+		}
+
+		p.printColoredString(p.theme.String, line)
+
+		if last {
+			p.printColoredString(p.theme.String, `"""`)
+			p.level--
+			return
+		}
+
+		p.printNewline()
+	}
+
+	p.printLineStart()
+	p.printColoredString(p.theme.String, `"""`)
+	p.level--
+}
+
+// canBackquote returns whether a string can be represented as a raw string
+// literal. Contrary to strconv.CanBackquote, newline characters are allowed.
+func canBackquote(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, c := range s {
+		if c == '`' || c == '\ufeff' ||
+			(c != '\n' && c != '\t' && unicode.IsControl(c)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func printableString(s string) bool {
+	if s == "" || !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, c := range s {
+		if !unicode.IsPrint(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func truncateString(s string, n int) string {
+	i := 0
+	for range n {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+
+	return s[:i]
 }
 
 func (p *Printer) printSequenceValue(v reflect.Value) {
 	if v.Kind() == reflect.Slice && v.IsNil() {
-		p.printString("nil")
+		p.printColoredString(p.theme.Keyword, "nil")
 	} else {
 		if v.Kind() == reflect.Slice {
 			first, annotation := p.pointerAnnotation(v.Pointer())
 			if annotation != "" {
-				p.printString(annotation)
+				p.printColoredString(p.theme.Annotation, annotation)
 				if !first {
 					return
 				}
 			}
+
+			p.printAddress(v.Pointer())
+			p.printLengths(v)
 		}
 
-		p.printByte('[')
+		if v.Len() > 0 && numericSequence(v) {
+			switch {
+			case p.numericSequenceStyle == NumericSequenceStyleSparkline:
+				p.printSparkline(v)
+				return
+
+			case p.numericSequenceStyle == NumericSequenceStyleHistogram:
+				p.printHistogram(v)
+				return
+
+			case p.numericSummaryThreshold > 0 &&
+				v.Len() >= p.numericSummaryThreshold:
+				p.printNumericSummary(v)
+				return
+			}
+		}
+
+		if p.matrixGrid && v.Len() > 0 && gridSequence(v) {
+			if !p.inline {
+				p.printGrid(v)
+				return
+			} else if p.measureWidth {
+				// Grids only make sense on multiple lines
+				p.overflow = true
+				return
+			}
+		}
+
+		p.printDelimiter("[")
 		if !p.inline {
 			p.printNewline()
 		}
 		p.level++
 
-		n := v.Len()
-		for i := range n {
-			ev := v.Index(i)
+		n := v.Len()
+		nbShown := p.nbShownElements(n)
+
+		padding := p.sequenceNumberPadding(v, nbShown)
+		rows := p.sequenceRowAlignment(v, nbShown)
+
+		for i := 0; i < nbShown; {
+			ev := v.Index(i)
+
+			if !p.inline {
+				p.printLineStart()
+			}
+
+			if padding != nil {
+				p.printPadding(padding[i])
+			}
+
+			p.rowAlignment = rows
+			p.printValue(ev)
+			p.rowAlignment = nil
+
+			count := 1
+			if p.collapseRepeats {
+				count = p.repeatCount(v, i, nbShown)
+			}
+
+			if count > 1 {
+				p.printByte(' ')
+				p.printColoredString(p.theme.Annotation,
+					"×"+strconv.Itoa(count))
+			}
+
+			i += count
+
+			if !p.inline || i < n {
+				p.printByte(',')
+			}
+
+			if p.inline {
+				if i < n {
+					p.printByte(' ')
+				}
+			} else {
+				p.printNewline()
+			}
+		}
+
+		if nbShown < n {
+			p.printRemainingElements(n - nbShown)
+		}
+
+		p.level--
+		if !p.inline {
+			p.printLineStart()
+		}
+		p.printDelimiter("]")
+	}
+}
+
+func (p *Printer) printByteSliceValue(v reflect.Value) {
+	if v.IsNil() {
+		p.printColoredString(p.theme.Keyword, "nil")
+		return
+	}
+
+	first, annotation := p.pointerAnnotation(v.Pointer())
+	if annotation != "" {
+		p.printColoredString(p.theme.Annotation, annotation)
+		if !first {
+			return
+		}
+	}
+
+	p.printAddress(v.Pointer())
+
+	data := v.Bytes()
+
+	n := len(data)
+	nbShown := p.nbShownElements(n)
+	data = data[:nbShown]
 
-			if !p.inline {
-				p.printLineStart()
-			}
+	switch p.byteSliceMode {
+	case ByteSliceModeString:
+		p.printStringValue(reflect.ValueOf(string(data)))
 
-			p.printValue(ev)
-			if !p.inline || i < n-1 {
-				p.printByte(',')
-			}
+		if nbShown < n {
+			p.printByte(' ')
+			p.printColoredString(p.theme.Annotation,
+				"… (+"+strconv.Itoa(n-nbShown)+" more)")
+		}
 
-			if p.inline {
-				if i < n-1 {
-					p.printByte(' ')
-				}
-			} else {
-				p.printNewline()
-			}
+	case ByteSliceModeHexDump:
+		if n == 0 {
+			p.printString("[]")
+			return
 		}
 
-		p.level--
-		if !p.inline {
-			p.printLineStart()
+		p.printHexDump(data, n)
+	}
+}
+
+// printHexDump prints data as a hexadecimal dump similar to the output of
+// hexdump -C; n is the total length of the original data, which may have been
+// truncated.
+func (p *Printer) printHexDump(data []byte, n int) {
+	nbShown := len(data)
+
+	if p.inline {
+		p.printByte('[')
+		p.printColoredString(p.theme.Number, hex.EncodeToString(data))
+		if nbShown < n {
+			p.printByte(' ')
+			p.printRemainingElements(n - nbShown)
 		}
 		p.printByte(']')
+		return
+	}
+
+	p.printByte('[')
+	p.printNewline()
+	p.level++
+
+	dump := strings.TrimSuffix(hex.Dump(data), "\n")
+	for _, line := range strings.Split(dump, "\n") {
+		p.printLineStart()
+		p.printString(line)
+		p.printNewline()
+	}
+
+	if nbShown < n {
+		p.printRemainingElements(n - nbShown)
 	}
+
+	p.level--
+	p.printLineStart()
+	p.printByte(']')
+}
+
+func isByteSlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
 }
 
 func (p *Printer) printMapValue(v reflect.Value) {
 	if v.IsNil() {
-		p.printString("nil")
+		p.printColoredString(p.theme.Keyword, "nil")
 	} else {
-		keys := v.MapKeys()
-
-		if len(keys) == 0 {
-			p.printString("{}")
+		if v.Len() == 0 {
+			p.printDelimiter("{}")
 			return
 		}
 
 		first, annotation := p.pointerAnnotation(v.Pointer())
 		if annotation != "" {
-			p.printString(annotation)
+			p.printColoredString(p.theme.Annotation, annotation)
 			if !first {
 				return
 			}
 		}
 
-		slices.SortFunc(keys, p.compareMapKeys)
+		keys := p.sortedMapKeys(v)
+
+		// Maps whose values are empty structures are used as sets, printing
+		// the values would only add noise.
+		set := setType(v.Type())
 
-		p.printByte('{')
+		p.printAddress(v.Pointer())
+		p.printLengths(v)
+		p.printDelimiter("{")
 		if !p.inline {
 			p.printNewline()
 		}
 		p.level++
 
 		n := len(keys)
+		nbShown := p.nbShownMapEntries(n)
 		i := 0
-		for _, kv := range keys {
+		for _, kv := range keys[:nbShown] {
 			vv := v.MapIndex(kv)
 
 			if !p.inline {
@@ -608,9 +2302,11 @@ func (p *Printer) printMapValue(v reflect.Value) {
 			}
 
 			p.printValue(kv)
-			p.printString(": ")
+			if !set {
+				p.printString(": ")
+				p.printValue(vv)
+			}
 
-			p.printValue(vv)
 			if !p.inline || i < n-1 {
 				p.printByte(',')
 			}
@@ -626,152 +2322,402 @@ func (p *Printer) printMapValue(v reflect.Value) {
 			i++
 		}
 
+		if nbShown < n {
+			p.printRemainingElements(n - nbShown)
+		}
+
 		p.level--
 		if !p.inline {
 			p.printLineStart()
 		}
-		p.printByte('}')
+		p.printDelimiter("}")
+	}
+}
+
+func (p *Printer) nbShownElements(n int) int {
+	if p.maxElements > 0 && n > p.maxElements {
+		return p.maxElements
+	}
+
+	return n
+}
+
+func setType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Elem().Kind() == reflect.Struct &&
+		t.Elem().NumField() == 0
+}
+
+func (p *Printer) nbShownMapEntries(n int) int {
+	if p.maxMapEntries > 0 {
+		return min(n, p.maxMapEntries)
+	}
+
+	return p.nbShownElements(n)
+}
+
+// sortedMapKeys returns the keys of a map in the order its entries are
+// printed.
+func (p *Printer) sortedMapKeys(v reflect.Value) []reflect.Value {
+	if p.mapEntryOrder != MapEntryOrderValuesAscending &&
+		p.mapEntryOrder != MapEntryOrderValuesDescending {
+		keys := v.MapKeys()
+		slices.SortFunc(keys, p.compareMapKeys)
+		return keys
+	}
+
+	type mapEntry struct {
+		key, value reflect.Value
+	}
+
+	entries := make([]mapEntry, 0, v.Len())
+
+	iter := v.MapRange()
+	for iter.Next() {
+		entries = append(entries, mapEntry{iter.Key(), iter.Value()})
+	}
+
+	slices.SortFunc(entries, func(e1, e2 mapEntry) int {
+		c := compareValues(e1.value, e2.value)
+		if p.mapEntryOrder == MapEntryOrderValuesDescending {
+			c = -c
+		}
+
+		if c == 0 {
+			c = p.compareMapKeys(e1.key, e2.key)
+		}
+
+		return c
+	})
+
+	keys := make([]reflect.Value, len(entries))
+	for i, e := range entries {
+		keys[i] = e.key
+	}
+
+	return keys
+}
+
+func (p *Printer) printRemainingElements(n int) {
+	p.printSummaryEntry("… (+" + strconv.Itoa(n) + " more)")
+}
+
+// printSummaryEntry prints the last entry of a sequence, map or structure,
+// used to indicate that some entries were not printed.
+func (p *Printer) printSummaryEntry(s string) {
+	if !p.inline {
+		p.printLineStart()
+	}
+
+	p.printColoredString(p.theme.Annotation, s)
+
+	if !p.inline {
+		p.printNewline()
 	}
 }
 
 func (p *Printer) compareMapKeys(v1, v2 reflect.Value) int {
-	k1 := v1.Kind()
-	k2 := v2.Kind()
+	if p.mapKeyCompare != nil {
+		return p.mapKeyCompare(v1, v2)
+	}
 
-	if k1 == k2 {
-		switch k1 {
-		case reflect.Bool:
-			b1, b2 := v1.Bool(), v2.Bool()
+	return compareValues(v1, v2)
+}
 
-			if !b1 && b2 {
-				return -1
-			} else if b1 && !b2 {
-				return 1
-			}
+// compareValues defines a total order on comparable values so that map keys
+// are always printed in the same order. Interface values are ordered by type
+// first, nil interfaces coming first.
+func compareValues(v1, v2 reflect.Value) int {
+	if v1.Kind() != v2.Kind() {
+		return cmp.Compare(v1.Kind(), v2.Kind())
+	}
 
-			return 0
+	switch v1.Kind() {
+	case reflect.Bool:
+		b1, b2 := v1.Bool(), v2.Bool()
 
-		case reflect.Int:
-			fallthrough
-		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			i1, i2 := v1.Int(), v2.Int()
+		if !b1 && b2 {
+			return -1
+		} else if b1 && !b2 {
+			return 1
+		}
 
-			if i1 < i2 {
-				return -1
-			} else if i2 < i1 {
-				return 1
-			}
+		return 0
 
-			return 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(v1.Int(), v2.Int())
 
-		case reflect.Uint:
-			fallthrough
-		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		case reflect.Uintptr:
-			u1, u2 := v1.Uint(), v2.Uint()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(v1.Uint(), v2.Uint())
 
-			if u1 < u2 {
-				return -1
-			} else if u2 < u1 {
-				return 1
-			}
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(v1.Float(), v2.Float())
 
-			return 0
+	case reflect.Complex64, reflect.Complex128:
+		c1, c2 := v1.Complex(), v2.Complex()
 
-		case reflect.Float32, reflect.Float64:
-			f1, f2 := v1.Float(), v2.Float()
+		if c := cmp.Compare(real(c1), real(c2)); c != 0 {
+			return c
+		}
 
-			if f1 < f2 {
-				return -1
-			} else if f2 < f1 {
-				return 1
+		return cmp.Compare(imag(c1), imag(c2))
+
+	case reflect.String:
+		return strings.Compare(v1.String(), v2.String())
+
+	case reflect.Chan, reflect.Pointer, reflect.UnsafePointer:
+		return cmp.Compare(v1.Pointer(), v2.Pointer())
+
+	case reflect.Struct:
+		for i := range v1.NumField() {
+			if c := compareValues(v1.Field(i), v2.Field(i)); c != 0 {
+				return c
 			}
+		}
 
-			return 0
+		return 0
 
-		case reflect.String:
-			return strings.Compare(v1.String(), v2.String())
+	case reflect.Array:
+		for i := range v1.Len() {
+			if c := compareValues(v1.Index(i), v2.Index(i)); c != 0 {
+				return c
+			}
+		}
 
-		case reflect.Chan, reflect.Pointer, reflect.UnsafePointer:
-			p1, p2 := v1.Pointer(), v2.Pointer()
+		return 0
 
-			if p1 < p2 {
-				return -1
-			} else if p2 < p1 {
+	case reflect.Interface:
+		if v1.IsNil() || v2.IsNil() {
+			if !v1.IsNil() {
 				return 1
+			} else if !v2.IsNil() {
+				return -1
 			}
 
 			return 0
+		}
 
-		default:
-			return 0
+		e1, e2 := v1.Elem(), v2.Elem()
+		if e1.Type() != e2.Type() {
+			return strings.Compare(e1.Type().String(), e2.Type().String())
 		}
+
+		return compareValues(e1, e2)
 	}
 
 	return 0
 }
 
 func (p *Printer) printStructValue(v reflect.Value) {
-	vt := v.Type()
+	fields, nbOmitted := p.nonZeroFields(v, p.structFields(v))
+
+	omissionString := " zero fields omitted)"
+	if p.structBaseline(v).IsValid() {
+		omissionString = " unchanged fields omitted)"
+	}
+
+	// Row alignment only applies to the structure printed as a sequence
+	// element, not to the structures it contains.
+	rows := p.rowAlignment
+	p.rowAlignment = nil
+	if rows != nil && (rows.structType != v.Type() || !p.inline) {
+		rows = nil
+	}
 
-	if vt.NumField() == 0 {
-		p.printString("{}")
+	column, labelWidth := p.structNumberColumn(fields)
+
+	if len(fields) == 0 && nbOmitted == 0 {
+		p.printDelimiter("{}")
 	} else {
-		p.printByte('{')
+		p.printDelimiter("{")
 		if !p.inline {
 			p.printNewline()
 		}
 		p.level++
 
-		n := vt.NumField()
-		for i := range n {
-			fv := v.Field(i)
-			ft := vt.Field(i)
-
-			if !ft.IsExported() && p.hidePrivateFields {
-				continue
-			}
-
+		n := len(fields)
+		for i, f := range fields {
 			if !p.inline {
 				p.printLineStart()
 			}
 
-			p.printString(ft.Name)
+			p.printColoredString(p.fieldNameColor(), f.label())
 			p.printString(": ")
 
-			p.printValue(fv)
-			if !p.inline || i < n-1 {
+			var rightPadding int
+			if column != nil {
+				padding := labelWidth - utf8.RuneCountInString(f.label())
+				if s, numeric := p.numericFieldString(f); numeric {
+					leftPadding, _ := column.padding(s, true)
+					padding += leftPadding
+				}
+
+				p.printPadding(padding)
+			} else if c := rows.column(f); c != nil {
+				var leftPadding int
+				leftPadding, rightPadding = c.padding(p.numericFieldString(f))
+				p.printPadding(leftPadding)
+			}
+
+			if f.redaction != "" {
+				p.printRedactedValue(f)
+			} else {
+				baseline := p.baselineValue
+				p.baselineValue = f.baseline
+				p.printFieldValue(f)
+				p.baselineValue = baseline
+			}
+
+			showTag := p.showStructTags && f.Tag != ""
+
+			if showTag && p.inline {
+				p.printColoredString(p.theme.Annotation,
+					" /* "+string(f.Tag)+" */")
+			}
+
+			if !p.inline || i < n-1 || nbOmitted > 0 {
 				p.printByte(',')
 			}
 
+			if showTag && !p.inline {
+				p.printColoredString(p.theme.Annotation,
+					" // "+string(f.Tag))
+			}
+
 			if p.inline {
-				if i < n-1 {
+				if i < n-1 || nbOmitted > 0 {
 					p.printByte(' ')
+					p.printPadding(rightPadding)
 				}
 			} else {
 				p.printNewline()
 			}
 		}
 
+		if nbOmitted > 0 {
+			p.printSummaryEntry(
+				"… (" + strconv.Itoa(nbOmitted) + omissionString)
+		}
+
 		p.level--
 		if !p.inline {
 			p.printLineStart()
 		}
-		p.printByte('}')
+		p.printDelimiter("}")
+	}
+}
+
+func (p *Printer) printFieldValue(f structField) {
+	if f.tag.integerBase != 0 {
+		integerBase := p.integerBase
+		defer func() { p.integerBase = integerBase }()
+
+		p.integerBase = f.tag.integerBase
+	}
+
+	if f.tag.byteSize {
+		switch f.value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			p.printByteSize(f.value.Int() < 0, absInt(f.value.Int()))
+			return
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64, reflect.Uintptr:
+			p.printByteSize(false, f.value.Uint())
+			return
+		}
+	}
+
+	p.printValue(f.value)
+}
+
+// printByteSize prints a number of bytes using binary units, followed by the
+// exact number if a unit is used.
+func (p *Printer) printByteSize(negative bool, n uint64) {
+	sign := ""
+	if negative {
+		sign = "-"
+	}
+
+	if n < 1024 {
+		p.printColoredString(p.theme.Number, sign+strconv.FormatUint(n, 10)+" B")
+		return
+	}
+
+	units := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+	size := float64(n) / 1024
+	unit := 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+
+	s := strconv.FormatFloat(size, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+
+	p.printColoredString(p.theme.Number, sign+s+" "+units[unit])
+	p.printColoredString(p.theme.Annotation,
+		" ("+sign+p.formatInteger(n)+")")
+}
+
+func absInt(i int64) uint64 {
+	if i < 0 {
+		return uint64(-i)
+	}
+
+	return uint64(i)
+}
+
+func (p *Printer) printRedactedValue(f structField) {
+	if f.tag.redact || !f.IsExported() {
+		p.printColoredString(p.theme.Annotation, f.redaction)
+	} else {
+		p.printColoredString(p.theme.String, strconv.Quote(f.redaction))
 	}
 }
 
+// printChannelValue prints the length and capacity of a channel; its address
+// is printed after the type by printValue so that it is easy to tell
+// channels apart.
 func (p *Printer) printChannelValue(v reflect.Value) {
-	p.printPointerAddressValue(v.Pointer())
+	if v.IsNil() {
+		p.printColoredString(p.theme.Keyword, "nil")
+		return
+	}
+
+	p.printString("len=")
+	p.printColoredString(p.theme.Number, strconv.Itoa(v.Len()))
+	p.printString(" cap=")
+	p.printColoredString(p.theme.Number, strconv.Itoa(v.Cap()))
 }
 
 func (p *Printer) printFunctionValue(v reflect.Value) {
-	p.printPointerAddressValue(v.Pointer())
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		p.printPointerAddressValue(v.Pointer())
+		return
+	}
+
+	// Remove the package path, keeping the package name, and the suffix of
+	// method values.
+	name := strings.TrimSuffix(shortFunctionName(fn.Name()), "-fm")
+
+	p.printString(name)
+
+	// Method values are wrappers generated by the compiler and do not have
+	// any useful location.
+	file, line := fn.FileLine(fn.Entry())
+	if file != "" && file != "<autogenerated>" {
+		location := filepath.Base(file) + ":" + strconv.Itoa(line)
+		p.printColoredString(p.theme.Annotation, " ("+location+")")
+	}
 }
 
 func (p *Printer) printInterfaceValue(v reflect.Value) {
 	if v.IsZero() {
-		p.printString("nil")
+		p.printColoredString(p.theme.Keyword, "nil")
 	} else {
 		p.printValue(v.Elem())
 	}
@@ -779,33 +2725,74 @@ func (p *Printer) printInterfaceValue(v reflect.Value) {
 
 func (p *Printer) printPointerValue(v reflect.Value) {
 	if v.IsZero() {
-		p.printString("nil")
+		p.printColoredString(p.theme.Keyword, "nil")
+	} else if !p.followPointer() {
+		p.printColoredString(p.theme.Type, p.valueTypeString(v))
+		p.printByte('(')
+		p.printColoredString(p.theme.Annotation,
+			formatPointerAddress(v.Pointer()))
+		p.printByte(')')
 	} else {
 		first, annotation := p.pointerAnnotation(v.Pointer())
 		if annotation != "" {
-			p.printString(annotation)
+			p.printColoredString(p.theme.Annotation, annotation)
 			if !first {
 				return
 			}
 		}
 
 		p.printByte('&')
+		p.printAddress(v.Pointer())
+
+		p.pointerDepth++
 		p.printValue(v.Elem())
+		p.pointerDepth--
+	}
+}
+
+// followPointer returns whether a pointer at the current pointer depth can be
+// dereferenced.
+func (p *Printer) followPointer() bool {
+	return p.maxPointerDepth <= 0 || p.pointerDepth < p.maxPointerDepth
+}
+
+func (p *Printer) printAddress(ptr uintptr) {
+	if p.showAddresses {
+		p.printColoredString(p.theme.Annotation,
+			"("+formatPointerAddress(ptr)+")")
+	}
+}
+
+func (p *Printer) printLengths(v reflect.Value) {
+	if !p.showLengths {
+		return
+	}
+
+	s := "(len=" + strconv.Itoa(v.Len())
+	if v.Kind() == reflect.Slice {
+		s += " cap=" + strconv.Itoa(v.Cap())
 	}
+	s += ")"
+
+	p.printColoredString(p.theme.Annotation, s)
 }
 
 func (p *Printer) printPointerAddressValue(ptr uintptr) {
 	if ptr == 0 {
-		p.printString("nil")
+		p.printColoredString(p.theme.Keyword, "nil")
 	} else {
-		switch uintptrSize {
-		case 4:
-			p.printFormat("%#08x", ptr)
-		case 8:
-			p.printFormat("%#016x", ptr)
-		default:
-			p.printFormat("%#x", ptr)
-		}
+		p.printString(formatPointerAddress(ptr))
+	}
+}
+
+func formatPointerAddress(ptr uintptr) string {
+	switch uintptrSize {
+	case 4:
+		return fmt.Sprintf("%#08x", ptr)
+	case 8:
+		return fmt.Sprintf("%#016x", ptr)
+	default:
+		return fmt.Sprintf("%#x", ptr)
 	}
 }
 
@@ -815,12 +2802,44 @@ func (p *Printer) printUnknownValue(v reflect.Value) {
 		// value with kind zero that panics if IsZero() is called. None of it
 		// makes any sense but the Go type/value system is fundamentally broken
 		// anyway.
-		p.printFormat("nil")
+		p.printColoredString(p.theme.Keyword, "nil")
 	} else {
 		p.printFormat("%#v", v)
 	}
 }
 
+func (p *Printer) elidedValue(v reflect.Value) bool {
+	if p.maxDepth <= 0 || p.level < p.maxDepth {
+		return false
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return v.Len() > 0
+	case reflect.Struct:
+		return v.NumField() > 0
+	}
+
+	return false
+}
+
+func (p *Printer) printElidedValue(v reflect.Value) {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		p.printLengths(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		p.printDelimiter("[")
+		p.printColoredString(p.theme.Annotation, "…")
+		p.printDelimiter("]")
+	default:
+		p.printDelimiter("{")
+		p.printColoredString(p.theme.Annotation, "…")
+		p.printDelimiter("}")
+	}
+}
+
 func (p *Printer) printValueString(v reflect.Value, s string) {
 	p.printString(s)
 }
@@ -851,8 +2870,40 @@ func (p *Printer) printTypeForValue(v reflect.Value) bool {
 	return true
 }
 
+// typeVisible returns whether the type of a value is visible when it is
+// printed, either before the value or before the value it points to.
+func (p *Printer) typeVisible(v reflect.Value) bool {
+	if p.printTypeForValue(v) {
+		return true
+	}
+
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		return p.printTypeForValue(v.Elem())
+	}
+
+	return false
+}
+
 func (p *Printer) valueTypeString(v reflect.Value) string {
-	s := v.Type().String()
+	if v.Type() == urlComponentsType {
+		return p.qualifiedTypeName("net/url", "URL")
+	}
+
+	if v.Type() == imageSummaryType || v.Type() == imagePreviewSummaryType {
+		return v.FieldByName("typeName").String()
+	}
+
+	// The implementation of reflect.Type is not exported
+	if v.Kind() == reflect.Pointer && v.Type().Implements(reflectTypeType) {
+		return p.qualifiedTypeName("reflect", "Type")
+	}
+
+	var s string
+	if setType(v.Type()) {
+		s = "set[" + p.typeString(v.Type().Key()) + "]"
+	} else {
+		s = p.typeString(v.Type())
+	}
 
 	// It does not seem possible to get the actual interface type behind a
 	// variable. I.e. reflect.TypeOf(any(42)).Kind() is reflect.Int, not
@@ -864,13 +2915,21 @@ func (p *Printer) valueTypeString(v reflect.Value) string {
 }
 
 func (p *Printer) addThousandsSeparator(s string) string {
-	cs2 := make([]rune, len(s))
+	if s2, found := strings.CutPrefix(s, "-"); found {
+		return "-" + p.groupDigits(s2, 3)
+	}
+
+	return p.groupDigits(s, 3)
+}
+
+func (p *Printer) groupDigits(s string, groupSize int) string {
+	cs2 := make([]rune, 0, len(s)+len(s)/groupSize)
 
 	cs := []rune(s)
 	slices.Reverse(cs)
 
 	for i, c := range cs {
-		if i > 0 && i%3 == 0 {
+		if i > 0 && i%groupSize == 0 {
 			cs2 = append(cs2, p.thousandsSeparator)
 		}
 
@@ -883,13 +2942,33 @@ func (p *Printer) addThousandsSeparator(s string) string {
 }
 
 func (p *Printer) inlinableValue(v reflect.Value) bool {
+	if p.inlinable != nil {
+		return p.inlinable(v)
+	}
+
+	return p.defaultInlinableValue(v)
+}
+
+func (p *Printer) defaultInlinableValue(v reflect.Value) bool {
+	if c, ok := collectionContent(v); ok {
+		v = c
+	}
+
 	if v.Kind() == 0 || p.atomicValue(v) {
 		return true
 	}
 
+	if p.byteSliceMode == ByteSliceModeHexDump && isByteSlice(v) {
+		return v.Len() == 0
+	}
+
+	if err, ok := p.errorValue(v); ok {
+		return len(unwrapError(err)) == 0
+	}
+
 	switch v.Kind() {
 	case reflect.Array, reflect.Slice:
-		for i := range v.Len() {
+		for i := range p.nbShownElements(v.Len()) {
 			if ev := v.Index(i); !p.atomicValue(ev) {
 				return false
 			}
@@ -898,9 +2977,15 @@ func (p *Printer) inlinableValue(v reflect.Value) bool {
 		return true
 
 	case reflect.Map:
+		set := setType(v.Type())
+
 		iter := v.MapRange()
 		for iter.Next() {
-			if !p.atomicValue(iter.Value()) {
+			if set && !p.atomicValue(iter.Key()) {
+				return false
+			}
+
+			if !set && !p.atomicValue(iter.Value()) {
 				return false
 			}
 		}
@@ -908,8 +2993,14 @@ func (p *Printer) inlinableValue(v reflect.Value) bool {
 		return true
 
 	case reflect.Struct:
-		for i := range v.NumField() {
-			if fv := v.Field(i); !p.atomicValue(fv) {
+		fields, _ := p.nonZeroFields(v, p.structFields(v))
+		for _, f := range fields {
+			if f.redaction == "" && !p.atomicValue(f.value) {
+				return false
+			}
+
+			// Tags are printed as line comments
+			if p.showStructTags && f.Tag != "" {
 				return false
 			}
 		}
@@ -935,9 +3026,15 @@ func (p *Printer) atomicValue(v reflect.Value) bool {
 		reflect.UnsafePointer,
 	}
 
-	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
-		return p.atomicValue(v.Elem())
+	// Pointers and interfaces can form cycles without any other value, e.g.
+	// an interface value containing a pointer to itself.
+	for range maxRecursionDepth {
+		if v.Kind() != reflect.Interface && v.Kind() != reflect.Pointer {
+			return slices.Contains(atomicKinds, v.Kind())
+		}
+
+		v = v.Elem()
 	}
 
-	return slices.Contains(atomicKinds, v.Kind())
+	return false
 }
@@ -0,0 +1,30 @@
+package pp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInlineAttemptPointerReferences(t *testing.T) {
+	n := 123456789
+
+	value := make([]*int, 40)
+	for i := range value {
+		value[i] = &n
+	}
+
+	p := NewPrinter(WithColors(false))
+
+	s := p.String(value)
+	if !strings.HasPrefix(s, "[]*int([\n  #1=&123_456_789,\n  #1#,\n") {
+		t.Errorf("pointer reference is not defined:\n%s", s)
+	}
+}
+
+func TestMaxStringLength(t *testing.T) {
+	p := NewPrinter(WithColors(false), WithMaxStringLength(3))
+
+	if s, output := p.String("héllo wörld"), `"hél"… (len=11)`; s != output {
+		t.Errorf("got %q, expected %q", s, output)
+	}
+}
@@ -0,0 +1,217 @@
+package pp
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Printf formats values with the fmt package, except for the %v and %+v verbs
+// which use the printer. Values printed with %v are printed on a single line;
+// values printed with %+v use the normal layout and their lines are indented
+// to the column where the value starts. Arguments used by other verbs,
+// including as "*" width or precision, are formatted by the fmt package.
+//
+// Values are first replaced by placeholders, since the column of a value is
+// only known once the entire string has been formatted.
+
+type printfArg struct {
+	index int
+	value any
+}
+
+func (a printfArg) Format(s fmt.State, verb rune) {
+	if verb != 'v' || s.Flag('#') {
+		fmt.Fprintf(s, fmt.FormatString(s, verb), a.value)
+		return
+	}
+
+	io.WriteString(s, "\x00"+strconv.Itoa(a.index))
+	if s.Flag('+') {
+		io.WriteString(s, "+")
+	}
+	io.WriteString(s, "\x00")
+}
+
+func Printf(format string, args ...any) (int, error) {
+	return DefaultPrinter.Printf(format, args...)
+}
+
+func Sprintf(format string, args ...any) string {
+	return DefaultPrinter.Sprintf(format, args...)
+}
+
+func (p *Printer) Printf(format string, args ...any) (int, error) {
+	s := p.Sprintf(format, args...)
+
+	p.mu.Lock()
+	w := p.defaultOutput
+	p.mu.Unlock()
+
+	if w == nil {
+		w = DefaultOutput
+	}
+
+	return io.WriteString(w, s)
+}
+
+func (p *Printer) Sprintf(format string, args ...any) string {
+	printfArgs := make([]any, len(args))
+	for i, wrap := range printfValueArgs(format, len(args)) {
+		if wrap {
+			printfArgs[i] = printfArg{index: i, value: args[i]}
+		} else {
+			printfArgs[i] = args[i]
+		}
+	}
+
+	s := fmt.Sprintf(format, printfArgs...)
+
+	var buf strings.Builder
+
+	for {
+		start := strings.IndexByte(s, 0)
+		if start == -1 {
+			break
+		}
+
+		end := strings.IndexByte(s[start+1:], 0)
+		if end == -1 {
+			break
+		}
+		end += start + 1
+
+		buf.WriteString(s[:start])
+
+		placeholder := s[start+1 : end]
+		s = s[end+1:]
+
+		expanded := strings.HasSuffix(placeholder, "+")
+		index, err := strconv.Atoi(strings.TrimSuffix(placeholder, "+"))
+		if err != nil || index >= len(args) {
+			// Not one of our placeholders
+			buf.WriteString("\x00" + placeholder + "\x00")
+			continue
+		}
+
+		if expanded {
+			line := buf.String()
+			if eol := strings.LastIndexByte(line, '\n'); eol >= 0 {
+				line = line[eol+1:]
+			}
+
+			indent := "\n" + strings.Repeat(" ", textWidth([]byte(line)))
+
+			value := p.String(args[index])
+			buf.WriteString(strings.ReplaceAll(value, "\n", indent))
+		} else {
+			p.renderInline(&buf, args[index])
+		}
+	}
+
+	buf.WriteString(s)
+
+	return buf.String()
+}
+
+// printfValueArgs returns, for each argument, whether it is only used by %v
+// verbs and must be printed by the printer. Other arguments, e.g. the ones
+// used by %T or as "*" width or precision, are passed to the fmt package
+// unchanged. Arguments are numbered the same way as in the fmt package,
+// including explicit argument indexes.
+func printfValueArgs(format string, nbArgs int) []bool {
+	used := make([]bool, nbArgs)
+	otherUse := make([]bool, nbArgs)
+
+	argNum := 0
+
+	use := func(verb byte) {
+		if argNum >= 0 && argNum < nbArgs {
+			used[argNum] = true
+			if verb != 'v' {
+				otherUse[argNum] = true
+			}
+		}
+
+		argNum++
+	}
+
+	// Parse an explicit argument index, e.g. "[2]"
+	argIndex := func(i int) int {
+		if i >= len(format) || format[i] != '[' {
+			return i
+		}
+
+		end := strings.IndexByte(format[i:], ']')
+		if end == -1 {
+			return i
+		}
+
+		n, err := strconv.Atoi(format[i+1 : i+end])
+		if err == nil {
+			argNum = n - 1
+		}
+
+		return i + end + 1
+	}
+
+	skipDigits := func(i int) int {
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+
+		return i
+	}
+
+	for i := 0; i < len(format); {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+		i++
+
+		for i < len(format) && strings.IndexByte("#0+- ", format[i]) >= 0 {
+			i++
+		}
+
+		i = argIndex(i)
+		if i < len(format) && format[i] == '*' {
+			use('*')
+			i++
+		} else {
+			i = skipDigits(i)
+		}
+
+		if i < len(format) && format[i] == '.' {
+			i = argIndex(i + 1)
+			if i < len(format) && format[i] == '*' {
+				use('*')
+				i++
+			} else {
+				i = skipDigits(i)
+			}
+		}
+
+		i = argIndex(i)
+
+		if i >= len(format) {
+			break
+		}
+
+		// Verbs can be any rune but only ASCII ones matter here
+		_, size := utf8.DecodeRuneInString(format[i:])
+		if verb := format[i]; verb != '%' {
+			use(verb)
+		}
+		i += size
+	}
+
+	wrap := make([]bool, nbArgs)
+	for i := range nbArgs {
+		wrap[i] = used[i] && !otherUse[i]
+	}
+
+	return wrap
+}
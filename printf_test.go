@@ -0,0 +1,26 @@
+package pp
+
+import "testing"
+
+func TestSprintfVerbs(t *testing.T) {
+	p := NewPrinter(WithColors(false))
+
+	tests := []struct {
+		format string
+		args   []any
+		output string
+	}{
+		{"%v", []any{[]int{1, 2}}, "[]int([1, 2])"},
+		{"%T", []any{[]int{1, 2}}, "[]int"},
+		{"%*d|%-*.*f", []any{5, 42, 6, 2, 3.14159}, "   42|3.14  "},
+		{"%v %[1]T", []any{1.5}, "1.5 float64"},
+		{"%d%% %q %v", []any{3, "a", true}, "3% \"a\" true"},
+	}
+
+	for _, test := range tests {
+		if s := p.Sprintf(test.format, test.args...); s != test.output {
+			t.Errorf("Sprintf(%q): got %q, expected %q",
+				test.format, s, test.output)
+		}
+	}
+}
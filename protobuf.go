@@ -0,0 +1,41 @@
+package pp
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Structures generated by the protobuf compiler contain internal fields which
+// are of no interest when printing messages. Messages are detected using the
+// methods of their pointer type so that we do not depend on any protobuf
+// library.
+
+var protobufMessageTypes sync.Map // reflect.Type -> bool
+
+func isProtobufMessageType(t reflect.Type) bool {
+	if value, found := protobufMessageTypes.Load(t); found {
+		return value.(bool)
+	}
+
+	pt := reflect.PointerTo(t)
+
+	_, isMessage := pt.MethodByName("ProtoReflect")
+	if !isMessage {
+		// Messages generated by older versions of the compiler
+		_, isMessage = pt.MethodByName("ProtoMessage")
+	}
+
+	protobufMessageTypes.Store(t, isMessage)
+
+	return isMessage
+}
+
+func protobufInternalField(name string) bool {
+	switch name {
+	case "state", "sizeCache", "unknownFields":
+		return true
+	}
+
+	return strings.HasPrefix(name, "XXX_")
+}
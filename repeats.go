@@ -0,0 +1,33 @@
+package pp
+
+import (
+	"reflect"
+)
+
+// repeatCount returns the number of consecutive elements of a sequence,
+// starting at index i and stopping before index n, which have the same
+// representation.
+func (p *Printer) repeatCount(v reflect.Value, i, n int) int {
+	ev := v.Index(i)
+
+	j := i + 1
+
+	switch k := ev.Kind(); {
+	case k == reflect.Bool || k == reflect.String || numericKind(k):
+		for j < n && ev.Equal(v.Index(j)) {
+			j++
+		}
+
+	default:
+		// Rendering values must not change the state of pointer references
+		restorePointerReferences := p.savePointerReferences()
+		defer restorePointerReferences()
+
+		s := p.plainString(ev)
+		for j < n && p.plainString(v.Index(j)) == s {
+			j++
+		}
+	}
+
+	return j - i
+}
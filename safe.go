@@ -0,0 +1,15 @@
+//go:build ppsafe
+
+package pp
+
+import (
+	"reflect"
+)
+
+// With the "ppsafe" build tag, the unsafe package is never used and the value
+// of non-exported fields is not printed.
+const unsafeAccess = false
+
+func exposedValue(v reflect.Value) reflect.Value {
+	return v
+}
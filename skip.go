@@ -0,0 +1,41 @@
+package pp
+
+import (
+	"path"
+	"reflect"
+	"slices"
+)
+
+// skippedValue returns whether a value is of a type which must not be printed.
+// Skipping a type also skips pointers to this type.
+func (p *Printer) skippedValue(v reflect.Value) bool {
+	if v.Kind() == 0 || (len(p.skipTypes) == 0 && len(p.skipTypePatterns) == 0) {
+		return false
+	}
+
+	t := v.Type()
+	if p.skippedType(t) {
+		return true
+	}
+
+	return t.Kind() == reflect.Pointer && p.skippedType(t.Elem())
+}
+
+func (p *Printer) skippedType(t reflect.Type) bool {
+	if slices.Contains(p.skipTypes, t) {
+		return true
+	}
+
+	name := t.String()
+	for _, pattern := range p.skipTypePatterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Printer) skippedValueString(v reflect.Value) string {
+	return "<omitted " + p.valueTypeString(v) + ">"
+}
@@ -0,0 +1,161 @@
+package pp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SlogHandler is a slog.Handler printing the message of each record on a
+// single line followed by its attributes, each of them being pretty printed.
+type SlogHandler struct {
+	printer *Printer
+	options slog.HandlerOptions
+
+	attrs  []slog.Attr
+	groups []string
+
+	w  io.Writer
+	mu *sync.Mutex
+}
+
+func NewSlogHandler(w io.Writer, p *Printer, options *slog.HandlerOptions) *SlogHandler {
+	if p == nil {
+		p = &DefaultPrinter
+	}
+
+	h := SlogHandler{
+		printer: p,
+
+		w:  w,
+		mu: &sync.Mutex{},
+	}
+
+	if options != nil {
+		h.options = *options
+	}
+
+	return &h
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.options.Level != nil {
+		minLevel = h.options.Level.Level()
+	}
+
+	return level >= minLevel
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+
+	if !r.Time.IsZero() {
+		buf.WriteString(r.Time.Format("2006-01-02T15:04:05.000Z07:00"))
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(r.Level.String())
+	buf.WriteByte(' ')
+
+	if h.options.AddSource && r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		frame, _ := frames.Next()
+
+		buf.WriteString(frame.File)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(frame.Line))
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(r.Message)
+	buf.WriteByte('\n')
+
+	for _, attr := range h.attrs {
+		h.writeAttr(&buf, attr)
+	}
+
+	r.Attrs(func(attr slog.Attr) bool {
+		h.writeAttr(&buf, h.qualifyAttr(attr))
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+
+	h2.attrs = slices.Clone(h.attrs)
+	for _, attr := range attrs {
+		h2.attrs = append(h2.attrs, h.qualifyAttr(attr))
+	}
+
+	return &h2
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+
+	return &h2
+}
+
+// qualifyAttr prefixes the key of an attribute with the current group names,
+// which is simpler than nesting attributes since each one is printed
+// separately.
+func (h *SlogHandler) qualifyAttr(attr slog.Attr) slog.Attr {
+	if len(h.groups) > 0 {
+		attr.Key = strings.Join(h.groups, ".") + "." + attr.Key
+	}
+
+	return attr
+}
+
+func (h *SlogHandler) writeAttr(buf *bytes.Buffer, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	if h.options.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.options.ReplaceAttr(h.groups, attr)
+		attr.Value = attr.Value.Resolve()
+	}
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, attr2 := range attr.Value.Group() {
+			if attr.Key != "" {
+				attr2.Key = attr.Key + "." + attr2.Key
+			}
+
+			h.writeAttr(buf, attr2)
+		}
+
+		return
+	}
+
+	data := h.printer.renderValue(h.w, attr.Value.Any(), "%s", attr.Key)
+	data = bytes.TrimSuffix(data, []byte{'\n'})
+
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		buf.WriteString("  ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+}
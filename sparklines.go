@@ -0,0 +1,178 @@
+package pp
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// The maximum number of characters of a sparkline; longer sequences are
+	// split in buckets whose mean is used.
+	sparklineMaxWidth = 64
+
+	histogramNbBins   = 10
+	histogramMaxWidth = 40
+)
+
+var sparklineChars = []rune("▁▂▃▄▅▆▇█")
+
+// nonFiniteValuesString returns the annotation reporting the number of NaN and
+// infinite values of a sequence of numbers which were ignored.
+func nonFiniteValuesString(n int) string {
+	return "(" + strconv.Itoa(n) + " non-finite values)"
+}
+
+// numericValues returns the finite values of a sequence of numbers with their
+// minimum and maximum, and the number of NaN and infinite values ignored.
+func numericValues(v reflect.Value) ([]float64, float64, float64, int) {
+	values := make([]float64, 0, v.Len())
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+
+	var nbNonFinite int
+	for i := range v.Len() {
+		f := numericValue(v.Index(i))
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			nbNonFinite++
+			continue
+		}
+
+		values = append(values, f)
+		minValue = min(minValue, f)
+		maxValue = max(maxValue, f)
+	}
+
+	return values, minValue, maxValue, nbNonFinite
+}
+
+func (p *Printer) printSparkline(v reflect.Value) {
+	values, minValue, maxValue, nbNonFinite := numericValues(v)
+	if len(values) == 0 {
+		p.printNumericSummary(v)
+		return
+	}
+
+	if len(values) > sparklineMaxWidth {
+		buckets := make([]float64, sparklineMaxWidth)
+		for i := range buckets {
+			start := i * len(values) / sparklineMaxWidth
+			end := (i + 1) * len(values) / sparklineMaxWidth
+
+			var sum float64
+			for _, f := range values[start:end] {
+				sum += f
+			}
+
+			buckets[i] = sum / float64(end-start)
+		}
+
+		values = buckets
+	}
+
+	var buf strings.Builder
+	for _, f := range values {
+		i := 0
+		if maxValue > minValue {
+			ratio := (f - minValue) / (maxValue - minValue)
+			i = int(math.Round(ratio * float64(len(sparklineChars)-1)))
+		}
+
+		buf.WriteRune(sparklineChars[i])
+	}
+
+	p.printByte('[')
+	p.printColoredString(p.theme.Number, buf.String())
+	p.printColoredString(p.theme.Annotation,
+		" "+p.summaryFloatString(minValue)+"…"+p.summaryFloatString(maxValue))
+	if nbNonFinite > 0 {
+		p.printColoredString(p.theme.Annotation,
+			" "+nonFiniteValuesString(nbNonFinite))
+	}
+	p.printByte(']')
+}
+
+// printHistogram prints the distribution of the values of a sequence of
+// numbers with one line per bin. Histograms cannot be printed on a single
+// line, sparklines are used instead.
+func (p *Printer) printHistogram(v reflect.Value) {
+	if p.inline {
+		if p.measureWidth {
+			p.overflow = true
+			return
+		}
+
+		p.printSparkline(v)
+		return
+	}
+
+	values, minValue, maxValue, nbNonFinite := numericValues(v)
+	if len(values) == 0 {
+		p.printNumericSummary(v)
+		return
+	}
+
+	nbBins := histogramNbBins
+	if maxValue == minValue {
+		nbBins = 1
+	}
+
+	counts := make([]int, nbBins)
+	for _, f := range values {
+		bin := 0
+		if maxValue > minValue {
+			bin = int((f - minValue) / (maxValue - minValue) * float64(nbBins))
+			bin = min(bin, nbBins-1)
+		}
+
+		counts[bin]++
+	}
+
+	maxCount := 0
+	for _, count := range counts {
+		maxCount = max(maxCount, count)
+	}
+
+	labels := make([]string, nbBins)
+	labelWidth := 0
+	for i := range nbBins {
+		width := (maxValue - minValue) / float64(nbBins)
+		start := minValue + float64(i)*width
+
+		labels[i] = p.summaryFloatString(start) + " … " +
+			p.summaryFloatString(start+width)
+		labelWidth = max(labelWidth, len([]rune(labels[i])))
+	}
+
+	p.printByte('[')
+	p.printNewline()
+	p.level++
+
+	for i, count := range counts {
+		label := labels[i]
+		label += strings.Repeat(" ", labelWidth-len([]rune(label)))
+
+		barWidth := count * histogramMaxWidth / maxCount
+		if count > 0 {
+			barWidth = max(barWidth, 1)
+		}
+
+		p.printLineStart()
+		p.printColoredString(p.theme.Annotation, label)
+		p.printString(" |")
+		p.printColoredString(p.theme.Number, strings.Repeat("#", barWidth))
+		p.printString(" " + strconv.Itoa(count))
+		p.printNewline()
+	}
+
+	if nbNonFinite > 0 {
+		p.printLineStart()
+		p.printColoredString(p.theme.Annotation,
+			"… "+nonFiniteValuesString(nbNonFinite))
+		p.printNewline()
+	}
+
+	p.level--
+	p.printLineStart()
+	p.printByte(']')
+}
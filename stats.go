@@ -0,0 +1,127 @@
+package pp
+
+import (
+	"cmp"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+func numericSequence(v reflect.Value) bool {
+	switch v.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// printNumericSummary prints summary statistics of a sequence of numbers
+// instead of its elements. Percentiles use the nearest-rank method so that
+// they are always values of the sequence; NaN and infinite values are ignored
+// and only counted.
+func (p *Printer) printNumericSummary(v reflect.Value) {
+	n := v.Len()
+
+	elems := make([]reflect.Value, 0, n)
+	values := make([]float64, 0, n)
+
+	var nbNonFinite int
+	for i := range n {
+		ev := v.Index(i)
+
+		f := numericValue(ev)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			nbNonFinite++
+			continue
+		}
+
+		elems = append(elems, ev)
+		values = append(values, f)
+	}
+
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	slices.SortStableFunc(indexes, func(i, j int) int {
+		return cmp.Compare(values[i], values[j])
+	})
+
+	parts := []string{"len=" + strconv.Itoa(n)}
+
+	if len(values) > 0 {
+		var sum float64
+		for _, f := range values {
+			sum += f
+		}
+		mean := sum / float64(len(values))
+
+		percentile := func(pc int) string {
+			rank := int(math.Ceil(float64(pc) / 100 * float64(len(values))))
+			return p.numberString(elems[indexes[max(rank, 1)-1]])
+		}
+
+		parts = append(parts,
+			"min="+p.numberString(elems[indexes[0]]),
+			"max="+p.numberString(elems[indexes[len(indexes)-1]]),
+			"mean="+p.summaryFloatString(mean),
+			"p50="+percentile(50),
+			"p90="+percentile(90),
+			"p99="+percentile(99))
+	}
+
+	if nbNonFinite > 0 {
+		parts = append(parts, nonFiniteValuesString(nbNonFinite))
+	}
+
+	p.printByte('[')
+	p.printColoredString(p.theme.Annotation, strings.Join(parts, " "))
+	p.printByte(']')
+}
+
+func numericValue(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+
+	return math.NaN()
+}
+
+// summaryFloatString formats floating point numbers of summaries. Unless a
+// precision was set, numbers are rounded to four significant digits: the
+// exact value of each statistic is rarely interesting.
+func (p *Printer) summaryFloatString(f float64) string {
+	if p.floatPrecision >= 0 {
+		return p.formatFloat(f, 64)
+	}
+
+	return strconv.FormatFloat(f, 'g', 4, 64)
+}
+
+// numberString returns the representation of a number in a summary.
+func (p *Printer) numberString(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := v.Int(); i < 0 {
+			return "-" + p.formatInteger(uint64(-i))
+		} else {
+			return p.formatInteger(uint64(i))
+		}
+	case reflect.Float32, reflect.Float64:
+		return p.summaryFloatString(v.Float())
+	}
+
+	return p.formatInteger(v.Uint())
+}
@@ -0,0 +1,37 @@
+package pp
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNumericSequencesNonFinite(t *testing.T) {
+	values := []float64{1, 2, math.Inf(1), 3, math.NaN(), math.Inf(-1), 5}
+
+	tests := []struct {
+		opts   []Option
+		output string
+	}{
+		{
+			[]Option{WithNumericSummaryThreshold(2)},
+			"min=1 max=5 mean=2.75 p50=2 p90=5 p99=5 (3 non-finite values)",
+		},
+		{
+			[]Option{WithNumericSequenceStyle(NumericSequenceStyleHistogram)},
+			"… (3 non-finite values)",
+		},
+		{
+			[]Option{WithNumericSequenceStyle(NumericSequenceStyleSparkline)},
+			"[▁▃▅█ 1…5 (3 non-finite values)]",
+		},
+	}
+
+	for _, test := range tests {
+		p := NewPrinter(append(test.opts, WithColors(false))...)
+
+		if s := p.String(values); !strings.Contains(s, test.output) {
+			t.Errorf("output does not contain %q:\n%s", test.output, s)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package pp
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// Synchronization primitives are printed with a summary of their state. Their
+// internal fields are read with reflection since there is no way to access
+// their state otherwise; if their implementation changes and fields cannot be
+// found, they are printed as any other structure.
+
+var (
+	mutexType     = reflect.TypeFor[sync.Mutex]()
+	rwMutexType   = reflect.TypeFor[sync.RWMutex]()
+	waitGroupType = reflect.TypeFor[sync.WaitGroup]()
+	syncMapType   = reflect.TypeFor[sync.Map]()
+	anyType       = reflect.TypeFor[any]()
+)
+
+const (
+	mutexLocked       = 1
+	rwMutexMaxReaders = 1 << 30
+)
+
+func formatSyncValue(v reflect.Value) any {
+	switch v.Type() {
+	case mutexType:
+		state, ok := findIntField(v, "state")
+		if !ok {
+			return nil
+		}
+
+		return RawString(mutexStateString(state))
+
+	case rwMutexType:
+		w, ok := findField(v, "w")
+		if !ok {
+			return nil
+		}
+
+		state, ok := findIntField(w, "state")
+		if !ok {
+			return nil
+		}
+
+		readerCount, ok := findField(v, "readerCount")
+		if !ok {
+			return nil
+		}
+
+		nbReaders, ok := findIntField(readerCount, "v")
+		if !ok {
+			return nil
+		}
+
+		// A writer waiting or holding the lock subtracts the maximum number
+		// of readers from the counter.
+		if nbReaders < 0 {
+			nbReaders += rwMutexMaxReaders
+		}
+
+		s := mutexStateString(state)
+		if nbReaders > 0 {
+			if state&mutexLocked == 0 {
+				s = "read-locked"
+			}
+
+			s += ", readers=" + strconv.FormatInt(nbReaders, 10)
+		}
+
+		return RawString(s)
+
+	case waitGroupType:
+		state, ok := findField(v, "state")
+		if !ok {
+			return nil
+		}
+
+		n, ok := findIntField(state, "v")
+		if !ok {
+			return nil
+		}
+
+		// The counter is stored in the high 32 bits of the state
+		counter := int32(uint64(n) >> 32)
+
+		return RawString("counter=" + strconv.FormatInt(int64(counter), 10))
+	}
+
+	return nil
+}
+
+func mutexStateString(state int64) string {
+	if state&mutexLocked != 0 {
+		return "locked"
+	}
+
+	return "unlocked"
+}
+
+// findField returns the first field with a specific name in a structure,
+// looking into nested structures.
+func findField(v reflect.Value, name string) (reflect.Value, bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	if f := v.FieldByName(name); f.IsValid() {
+		return f, true
+	}
+
+	for i := range v.NumField() {
+		if f, ok := findField(v.Field(i), name); ok {
+			return f, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func findIntField(v reflect.Value, name string) (int64, bool) {
+	f, ok := findField(v, name)
+	if !ok {
+		return 0, false
+	}
+
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return f.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return int64(f.Uint()), true
+	}
+
+	return 0, false
+}
+
+// syncMapContent returns the content of a sync.Map value as a regular map so
+// that it can be printed as any other map. Keys and values have the type of
+// their content if they all have the same, and the any type otherwise.
+func syncMapContent(v reflect.Value) (reflect.Value, bool) {
+	if v.Type() != syncMapType {
+		return reflect.Value{}, false
+	}
+
+	m, ok := valueAs[*sync.Map](v)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	var keys, values []reflect.Value
+	m.Range(func(key, value any) bool {
+		keys = append(keys, reflect.ValueOf(key))
+		values = append(values, reflect.ValueOf(value))
+		return true
+	})
+
+	keyType, valueType := commonType(keys), commonType(values)
+
+	content := reflect.MakeMapWithSize(reflect.MapOf(keyType, valueType),
+		len(keys))
+	for i, key := range keys {
+		// Nil interface values yield invalid values which cannot be stored
+		if !key.IsValid() {
+			key = reflect.Zero(keyType)
+		}
+
+		value := values[i]
+		if !value.IsValid() {
+			value = reflect.Zero(valueType)
+		}
+
+		content.SetMapIndex(key, value)
+	}
+
+	return content, true
+}
+
+func commonType(values []reflect.Value) reflect.Type {
+	if len(values) == 0 || !values[0].IsValid() {
+		return anyType
+	}
+
+	t := values[0].Type()
+	for _, v := range values[1:] {
+		if !v.IsValid() || v.Type() != t {
+			return anyType
+		}
+	}
+
+	return t
+}
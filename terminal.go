@@ -0,0 +1,39 @@
+package pp
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+func (p *Printer) detectTerminal(w io.Writer) {
+	f, ok := w.(*os.File)
+	if !ok || !isTerminal(f) {
+		p.colors = false
+		return
+	}
+
+	p.colors = os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+
+	if width, ok := terminalWidth(f); ok {
+		p.maxInlineColumn = width
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func terminalWidthFromEnv() (int, bool) {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width <= 0 {
+		return 0, false
+	}
+
+	return width, true
+}
@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd) || ppsafe
+
+package pp
+
+import (
+	"os"
+)
+
+func terminalWidth(f *os.File) (int, bool) {
+	return terminalWidthFromEnv()
+}
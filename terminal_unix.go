@@ -0,0 +1,26 @@
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd) && !ppsafe
+
+package pp
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+func terminalWidth(f *os.File) (int, bool) {
+	var ws struct {
+		Row    uint16
+		Col    uint16
+		Xpixel uint16
+		Ypixel uint16
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(),
+		uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.Col == 0 {
+		return terminalWidthFromEnv()
+	}
+
+	return int(ws.Col), true
+}
@@ -0,0 +1,72 @@
+package pp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Log(t testing.TB, value any, label ...any) {
+	t.Helper()
+	DefaultPrinter.Log(t, value, label...)
+}
+
+// Log prints a value with the log function of a test. Values printed on
+// multiple lines start on their own line so that the indentation added by the
+// testing package does not break alignment.
+func (p *Printer) Log(t testing.TB, value any, label ...any) {
+	t.Helper()
+
+	s := p.String(value, label...)
+	if len(label) == 0 && strings.Contains(s, "\n") {
+		s = "\n" + s
+	}
+
+	t.Log(s)
+}
+
+func AssertEqual(t testing.TB, want, got any) bool {
+	t.Helper()
+	return DefaultPrinter.AssertEqual(t, want, got)
+}
+
+func RequireEqual(t testing.TB, want, got any) {
+	t.Helper()
+	DefaultPrinter.RequireEqual(t, want, got)
+}
+
+// AssertEqual compares two values with reflect.DeepEqual and reports a test
+// error with the differences between them if they are not equal. It returns
+// whether the values are equal.
+func (p *Printer) AssertEqual(t testing.TB, want, got any) bool {
+	t.Helper()
+
+	if reflect.DeepEqual(want, got) {
+		return true
+	}
+
+	t.Error(p.equalityFailure(want, got))
+	return false
+}
+
+// RequireEqual is similar to AssertEqual but stops the test if the values are
+// not equal.
+func (p *Printer) RequireEqual(t testing.TB, want, got any) {
+	t.Helper()
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatal(p.equalityFailure(want, got))
+	}
+}
+
+func (p *Printer) equalityFailure(want, got any) string {
+	diff := p.Diff(want, got)
+	if diff == "" {
+		// Values can be different in ways which are not printed, e.g. private
+		// fields when they are hidden.
+		return "values are not equal but have the same representation:\n" +
+			p.String(got)
+	}
+
+	return "values are not equal (-want +got):\n" + strings.TrimSuffix(diff, "\n")
+}
@@ -0,0 +1,60 @@
+package pp
+
+import (
+	"errors"
+	"testing"
+)
+
+// testRecorder records the failures reported by assertion functions.
+type testRecorder struct {
+	testing.TB
+
+	failures []string
+	fatal    bool
+}
+
+func (r *testRecorder) Helper() {}
+
+func (r *testRecorder) Error(args ...any) {
+	r.failures = append(r.failures, args[0].(string))
+}
+
+func (r *testRecorder) Fatal(args ...any) {
+	r.failures = append(r.failures, args[0].(string))
+	r.fatal = true
+}
+
+func TestAssertEqualNil(t *testing.T) {
+	p := NewPrinter(WithColors(false))
+
+	tests := []struct {
+		want, got any
+		equal     bool
+	}{
+		{nil, nil, true},
+		{nil, errors.New("foo"), false},
+		{42, nil, false},
+	}
+
+	for _, test := range tests {
+		var r testRecorder
+
+		if equal := p.AssertEqual(&r, test.want, test.got); equal != test.equal {
+			t.Errorf("AssertEqual(%#v, %#v): got %t, expected %t",
+				test.want, test.got, equal, test.equal)
+		}
+
+		if !test.equal && len(r.failures) != 1 {
+			t.Errorf("AssertEqual(%#v, %#v): no failure reported",
+				test.want, test.got)
+		}
+
+		r = testRecorder{}
+		p.RequireEqual(&r, test.want, test.got)
+
+		if r.fatal == test.equal {
+			t.Errorf("RequireEqual(%#v, %#v): got fatal %t, expected %t",
+				test.want, test.got, r.fatal, !test.equal)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package pp
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+)
+
+// formatTimeValue formats time values according to the time settings of the
+// printer. Time values are otherwise formatted by the formatting function, so
+// nothing is done when all settings have their default value.
+func (p *Printer) formatTimeValue(v reflect.Value) any {
+	if v.Type() != timeType {
+		return nil
+	}
+
+	if p.timeFormat == "" && p.timeLocation == nil && !p.showMonotonicClock &&
+		!p.relativeTimes {
+		return nil
+	}
+
+	value, ok := valueInterface(v)
+	if !ok {
+		return nil
+	}
+
+	t := value.(time.Time)
+
+	// Changing the location strips the monotonic clock reading, so we have to
+	// extract it first.
+	var monotonic string
+	if p.showMonotonicClock {
+		s := t.String()
+		if idx := strings.LastIndex(s, " m="); idx >= 0 {
+			monotonic = s[idx:]
+		}
+	}
+
+	if p.timeLocation != nil {
+		t = t.In(p.timeLocation)
+	}
+
+	layout := p.timeFormat
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+
+	var relative string
+	if p.relativeTimes && !t.IsZero() {
+		relative = " (" + relativeTime(time.Since(t)) + ")"
+	}
+
+	return RawString(t.Format(layout) + monotonic + relative)
+}
+
+// relativeTime formats the delta between a time and now, keeping the two most
+// significant units, e.g. "3h12m ago" or "in 2d5h".
+func relativeTime(d time.Duration) string {
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var s string
+	switch {
+	case d < time.Second:
+		return "now"
+	case d < time.Minute:
+		s = strconv.Itoa(int(d/time.Second)) + "s"
+	case d < time.Hour:
+		s = strconv.Itoa(int(d/time.Minute)) + "m" +
+			strconv.Itoa(int(d%time.Minute/time.Second)) + "s"
+	case d < 24*time.Hour:
+		s = strconv.Itoa(int(d/time.Hour)) + "h" +
+			strconv.Itoa(int(d%time.Hour/time.Minute)) + "m"
+	default:
+		s = strconv.Itoa(int(d/(24*time.Hour))) + "d" +
+			strconv.Itoa(int(d%(24*time.Hour)/time.Hour)) + "h"
+	}
+
+	if future {
+		return "in " + s
+	}
+
+	return s + " ago"
+}
+
+func (p *Printer) formatDurationValue(v reflect.Value) any {
+	if v.Type() != durationType {
+		return nil
+	}
+
+	d := time.Duration(v.Int())
+
+	switch p.durationStyle {
+	case DurationStyleHumanized:
+		return RawString(humanizeDuration(d))
+	case DurationStyleSeconds:
+		return RawString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+	}
+
+	return nil
+}
+
+// humanizeDuration formats a duration with separate hours, minutes and
+// seconds, e.g. "2h 15m 42.25s". Durations shorter than a second are
+// formatted the same way as time.Duration.String does.
+func humanizeDuration(d time.Duration) string {
+	if d > -time.Second && d < time.Second {
+		return d.String()
+	}
+
+	var sign string
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+
+	var parts []string
+	if h > 0 {
+		parts = append(parts, strconv.FormatInt(int64(h), 10)+"h")
+	}
+	if m > 0 {
+		parts = append(parts, strconv.FormatInt(int64(m), 10)+"m")
+	}
+	if d > 0 {
+		parts = append(parts, strconv.FormatFloat(d.Seconds(), 'f', -1, 64)+"s")
+	}
+
+	return sign + strings.Join(parts, " ")
+}
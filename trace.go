@@ -0,0 +1,67 @@
+package pp
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+func Trace(label string, args ...any) func(results ...any) {
+	return DefaultPrinter.Trace(label, args...)
+}
+
+// Trace prints a line indicating the entry in a function with its arguments,
+// and returns a function printing a line indicating the exit of the function
+// with the time elapsed since the call to Trace and optional results. It is
+// meant to be used with defer:
+//
+//	defer pp.Trace("process", id, opts)()
+//
+// Results can be printed by calling the returned function in a deferred
+// closure with named return values:
+//
+//	done := pp.Trace("process", id, opts)
+//	defer func() { done(n, err) }()
+func (p *Printer) Trace(label string, args ...any) func(results ...any) {
+	p.printTraceLine("→ " + label + "(" + p.traceValues(args) + ")")
+
+	start := time.Now()
+
+	return func(results ...any) {
+		elapsed := time.Since(start)
+
+		line := "← " + label + " (" + elapsed.String() + ")"
+		if len(results) > 0 {
+			line += ": " + p.traceValues(results)
+		}
+
+		p.printTraceLine(line)
+	}
+}
+
+func (p *Printer) traceValues(values []any) string {
+	var buf strings.Builder
+
+	for i, value := range values {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		p.renderInline(&buf, value)
+	}
+
+	return buf.String()
+}
+
+func (p *Printer) printTraceLine(line string) {
+	p = p.snapshot(nil)
+
+	p.reset(nil)
+	defer p.releaseBuffer()
+
+	if prefix := p.formatLabelPrefix(); prefix != "" {
+		line = prefix + " " + line
+	}
+
+	io.WriteString(p.defaultOutput, p.linePrefix+line+"\n")
+}
@@ -0,0 +1,257 @@
+package pp
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// The node tree is an intermediate representation of values used by output
+// formats other than the native one. Building it goes through the same steps
+// as native printing: value formatting functions, map key sorting and pointer
+// references. Build exposes it as a tree of Node values.
+
+func (p *Printer) buildNode(v reflect.Value) (result *Node) {
+	if p.depth >= maxRecursionDepth {
+		return scalarNode(ScalarString, "<max depth exceeded>")
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+
+	v = accessibleValue(v)
+
+	p.checkContext()
+
+	if p.skippedValue(v) {
+		return scalarNode(ScalarString, p.skippedValueString(v))
+	}
+
+	if !p.propagatePanics {
+		defer func() {
+			if value := recover(); value != nil {
+				if renderAborted(value) {
+					panic(value)
+				}
+
+				result = scalarNode(ScalarString, panicMessage(value))
+			}
+		}()
+	}
+
+	v, rawString, _ := p.formatValueChain(v)
+	if rawString != nil {
+		n := scalarNode(ScalarString, string(*rawString))
+		n.Type = p.valueTypeString(v)
+		return n
+	}
+
+	if v.Kind() == 0 {
+		return scalarNode(ScalarNil, "")
+	}
+
+	if err, ok := p.errorValue(v); ok {
+		n := scalarNode(ScalarString, err.Error())
+		n.Type = p.valueTypeString(v)
+		return n
+	}
+
+	if n := p.jsonValueNode(v); n != nil {
+		n.Type = p.valueTypeString(v)
+		return n
+	}
+
+	n := Node{Kind: NodeScalar, Type: p.valueTypeString(v)}
+
+	if c, ok := collectionContent(v); ok {
+		v = c
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		n.Scalar = ScalarBool
+		n.Value = strconv.FormatBool(v.Bool())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.Scalar = ScalarNumber
+		n.Value = strconv.FormatInt(v.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		n.Scalar = ScalarNumber
+		n.Value = strconv.FormatUint(v.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+
+		n.Scalar = ScalarNumber
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			n.Scalar = ScalarString
+		}
+
+		n.Value = strconv.FormatFloat(f, 'f', -1, v.Type().Bits())
+
+	case reflect.Complex64, reflect.Complex128:
+		n.Scalar = ScalarString
+		n.Value = strconv.FormatComplex(v.Complex(), 'f', -1, v.Type().Bits())
+
+	case reflect.String:
+		n.Scalar = ScalarString
+		n.Value = v.String()
+
+	case reflect.Array, reflect.Slice:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				n.Scalar = ScalarNil
+				break
+			}
+
+			first, ref := p.pointerReference(v.Pointer())
+			if ref > 0 && !first {
+				return &Node{Kind: NodeRef, ID: p.nodeRef(ref)}
+			}
+			n.ID = ref
+		}
+
+		n.Kind = NodeSeq
+		n.Elements = make([]*Node, v.Len())
+		for i := range v.Len() {
+			n.Elements[i] = p.buildNode(v.Index(i))
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			n.Scalar = ScalarNil
+			break
+		}
+
+		first, ref := p.pointerReference(v.Pointer())
+		if ref > 0 && !first {
+			return &Node{Kind: NodeRef, ID: p.nodeRef(ref)}
+		}
+		n.ID = ref
+
+		keys := p.sortedMapKeys(v)
+
+		if setType(v.Type()) {
+			n.Kind = NodeSeq
+			n.Elements = make([]*Node, len(keys))
+			for i, key := range keys {
+				n.Elements[i] = p.buildNode(key)
+			}
+			break
+		}
+
+		n.Kind = NodeMap
+		n.Entries = make([]NodeEntry, len(keys))
+		for i, key := range keys {
+			n.Entries[i] = NodeEntry{
+				Key:   p.buildNode(key),
+				Value: p.buildNode(v.MapIndex(key)),
+			}
+		}
+
+	case reflect.Struct:
+		n.Kind = NodeStruct
+		fields, _ := p.nonZeroFields(v, p.structFields(v))
+		for _, f := range fields {
+			field := NodeField{Name: f.label()}
+
+			if f.redaction != "" {
+				field.Value = scalarNode(ScalarString, f.redaction)
+			} else {
+				baseline := p.baselineValue
+				p.baselineValue = f.baseline
+				field.Value = p.buildNode(f.value)
+				p.baselineValue = baseline
+			}
+
+			n.Fields = append(n.Fields, field)
+		}
+
+	case reflect.Interface:
+		if v.IsNil() {
+			n.Scalar = ScalarNil
+			break
+		}
+
+		return p.buildNode(v.Elem())
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			n.Scalar = ScalarNil
+			break
+		}
+
+		if !p.followPointer() {
+			n.Scalar = ScalarString
+			n.Value = formatPointerAddress(v.Pointer())
+			break
+		}
+
+		first, ref := p.pointerReference(v.Pointer())
+		if ref > 0 && !first {
+			return &Node{Kind: NodeRef, ID: p.nodeRef(ref)}
+		}
+
+		p.pointerDepth++
+		elem := p.buildNode(v.Elem())
+		p.pointerDepth--
+
+		if ref > 0 {
+			if elem.Kind != NodeRef && elem.ID == 0 {
+				elem.ID = ref
+				return elem
+			}
+
+			// The element, e.g. a pointer or a map, is already referenced
+			// with its own number: references to the pointer become
+			// references to the element.
+			if elem.ID == ref {
+				// Pointer pointing to itself through an interface
+				n.Scalar = ScalarString
+				n.Value = "<cycle>"
+				n.ID = ref
+				return &n
+			}
+
+			p.nodeRefAliases[ref] = elem.ID
+		}
+
+		return elem
+
+	case reflect.Uintptr, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		var ptr uintptr
+		if v.Kind() == reflect.Uintptr {
+			ptr = uintptr(v.Uint())
+		} else {
+			ptr = v.Pointer()
+		}
+
+		if ptr == 0 {
+			n.Scalar = ScalarNil
+			break
+		}
+
+		n.Scalar = ScalarString
+		n.Value = formatPointerAddress(ptr)
+
+	default:
+		n.Scalar = ScalarNil
+	}
+
+	return &n
+}
+
+// nodeRef returns the number used in the node tree for a pointer reference
+// number.
+func (p *Printer) nodeRef(ref int) int {
+	for {
+		target, found := p.nodeRefAliases[ref]
+		if !found {
+			return ref
+		}
+
+		ref = target
+	}
+}
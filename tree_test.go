@@ -0,0 +1,41 @@
+package pp
+
+import (
+	"regexp"
+	"testing"
+)
+
+type testTreeValue struct {
+	N int
+}
+
+func TestTreeSharedPointerToPointer(t *testing.T) {
+	v := &testTreeValue{N: 1}
+	pv := &v
+
+	values := []any{
+		[]any{pv, pv},
+		[]any{pv, pv, v},
+		[]any{v, pv, pv},
+	}
+
+	idRe := regexp.MustCompile(`"\$id": "(#\d+)"`)
+	refRe := regexp.MustCompile(`"\$ref": "(#\d+)"`)
+
+	p := NewPrinter(WithColors(false), WithFormat(FormatJSON))
+
+	for _, value := range values {
+		s := p.String(value)
+
+		ids := make(map[string]bool)
+		for _, match := range idRe.FindAllStringSubmatch(s, -1) {
+			ids[match[1]] = true
+		}
+
+		for _, match := range refRe.FindAllStringSubmatch(s, -1) {
+			if !ids[match[1]] {
+				t.Errorf("reference %s is not defined in:\n%s", match[1], s)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package pp
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func (p *Printer) typeString(t reflect.Type) string {
+	if p.typeNameStyle == TypeNameStylePackage || p.typeNameStyle == "" {
+		return t.String()
+	}
+
+	var buf strings.Builder
+	p.writeTypeString(&buf, t)
+	return buf.String()
+}
+
+// qualifiedTypeName returns the name of a named type according to the type
+// name style.
+func (p *Printer) qualifiedTypeName(pkgPath, name string) string {
+	if pkgPath == "" {
+		return name
+	}
+
+	switch p.typeNameStyle {
+	case TypeNameStyleShort:
+		return name
+	case TypeNameStyleFull:
+		return pkgPath + "." + name
+	}
+
+	pkgName := pkgPath
+	if i := strings.LastIndexByte(pkgName, '/'); i >= 0 {
+		pkgName = pkgName[i+1:]
+	}
+
+	return pkgName + "." + name
+}
+
+func (p *Printer) writeTypeString(buf *strings.Builder, t reflect.Type) {
+	if t.Name() != "" {
+		buf.WriteString(p.qualifiedTypeName(t.PkgPath(), t.Name()))
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		buf.WriteByte('*')
+		p.writeTypeString(buf, t.Elem())
+
+	case reflect.Slice:
+		buf.WriteString("[]")
+		p.writeTypeString(buf, t.Elem())
+
+	case reflect.Array:
+		buf.WriteByte('[')
+		buf.WriteString(strconv.Itoa(t.Len()))
+		buf.WriteByte(']')
+		p.writeTypeString(buf, t.Elem())
+
+	case reflect.Map:
+		buf.WriteString("map[")
+		p.writeTypeString(buf, t.Key())
+		buf.WriteByte(']')
+		p.writeTypeString(buf, t.Elem())
+
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			buf.WriteString("<-chan ")
+		case reflect.SendDir:
+			buf.WriteString("chan<- ")
+		default:
+			buf.WriteString("chan ")
+		}
+
+		p.writeTypeString(buf, t.Elem())
+
+	case reflect.Func:
+		buf.WriteString("func(")
+		for i := range t.NumIn() {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+
+			if t.IsVariadic() && i == t.NumIn()-1 {
+				buf.WriteString("...")
+				p.writeTypeString(buf, t.In(i).Elem())
+			} else {
+				p.writeTypeString(buf, t.In(i))
+			}
+		}
+		buf.WriteByte(')')
+
+		if t.NumOut() == 1 {
+			buf.WriteByte(' ')
+			p.writeTypeString(buf, t.Out(0))
+		} else if t.NumOut() > 1 {
+			buf.WriteString(" (")
+			for i := range t.NumOut() {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+
+				p.writeTypeString(buf, t.Out(i))
+			}
+			buf.WriteByte(')')
+		}
+
+	case reflect.Struct:
+		if t.NumField() == 0 {
+			buf.WriteString("struct {}")
+			return
+		}
+
+		buf.WriteString("struct {")
+		for i := range t.NumField() {
+			if i > 0 {
+				buf.WriteByte(';')
+			}
+			buf.WriteByte(' ')
+
+			f := t.Field(i)
+			if !f.Anonymous {
+				buf.WriteString(f.Name)
+				buf.WriteByte(' ')
+			}
+
+			p.writeTypeString(buf, f.Type)
+
+			if f.Tag != "" {
+				buf.WriteByte(' ')
+				buf.WriteString(strconv.Quote(string(f.Tag)))
+			}
+		}
+		buf.WriteString(" }")
+
+	default:
+		// Non-empty interface types are rare enough outside of named types
+		// that we do not bother rewriting their method sets.
+		buf.WriteString(t.String())
+	}
+}
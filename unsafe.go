@@ -0,0 +1,18 @@
+//go:build !ppsafe
+
+package pp
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Non-exported fields can be read with the unsafe package unless the "ppsafe"
+// build tag is set.
+const unsafeAccess = true
+
+// exposedValue returns an addressable value which can be converted to an
+// interface value even if it was obtained from a non-exported field.
+func exposedValue(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
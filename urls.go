@@ -0,0 +1,57 @@
+package pp
+
+import (
+	"net/url"
+	"reflect"
+)
+
+var (
+	urlType           = reflect.TypeFor[url.URL]()
+	urlComponentsType = reflect.TypeFor[urlComponents]()
+)
+
+// urlComponents is the representation of URLs when they are expanded. It is
+// printed with the url.URL type name.
+type urlComponents struct {
+	Scheme   string
+	User     string
+	Host     string
+	Path     string
+	Query    url.Values
+	Fragment string
+}
+
+func (p *Printer) formatURLValue(v reflect.Value) any {
+	if !p.expandURLs || v.Type() != urlType {
+		return nil
+	}
+
+	value, ok := valueInterface(v)
+	if !ok {
+		return nil
+	}
+
+	u := value.(url.URL)
+
+	c := urlComponents{
+		Scheme:   u.Scheme,
+		Host:     u.Host,
+		Path:     u.Path,
+		Query:    u.Query(),
+		Fragment: u.Fragment,
+	}
+
+	// Passwords are masked as with url.URL.Redacted
+	if u.User != nil {
+		c.User = u.User.Username()
+		if _, found := u.User.Password(); found {
+			c.User += ":xxxxx"
+		}
+	}
+
+	if u.Opaque != "" {
+		c.Path = u.Opaque
+	}
+
+	return c
+}
@@ -0,0 +1,65 @@
+package pp
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+)
+
+// FormatUUID formats 16 byte arrays as UUID strings, e.g.
+// "123e4567-e89b-12d3-a456-426614174000". It can be registered as formatter
+// for UUID types which are not detected automatically.
+func FormatUUID(v reflect.Value) any {
+	if !isUUIDArray(v.Type()) {
+		return nil
+	}
+
+	var data [16]byte
+	for i := range data {
+		data[i] = byte(v.Index(i).Uint())
+	}
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], data[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], data[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], data[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], data[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], data[10:])
+
+	return RawString(buf[:])
+}
+
+func RegisterUUIDType(t reflect.Type) {
+	DefaultPrinter.RegisterUUIDType(t)
+}
+
+func (p *Printer) RegisterUUIDType(t reflect.Type) {
+	p.RegisterFormatter(t, FormatUUID)
+}
+
+func (p *Printer) formatUUIDValue(v reflect.Value) any {
+	if !p.detectUUIDs {
+		return nil
+	}
+
+	t := v.Type()
+	if !isUUIDArray(t) {
+		return nil
+	}
+
+	name := strings.ToLower(t.Name())
+	if !strings.Contains(name, "uuid") && !strings.Contains(name, "guid") {
+		return nil
+	}
+
+	return FormatUUID(v)
+}
+
+func isUUIDArray(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 16 &&
+		t.Elem().Kind() == reflect.Uint8
+}
@@ -1,30 +1,49 @@
 package pp
 
 import (
+	"fmt"
+	"image"
 	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
 	"reflect"
 	"regexp"
 	"sync/atomic"
 	"time"
-	"unsafe"
 )
 
+var reflectTypeType = reflect.TypeFor[reflect.Type]()
+
 func FormatValue(v reflect.Value) any {
-	// If the value is a non-exported variable or field, we will not be able to
-	// call Interface() on it. Using the unsafe package allows us to work around
-	// it. Of course if the value is not addressable and we still cannot call
-	// Interface(), we cannot go any further and fall back to default
-	// formatting.
+	// All supported types are either structures, byte slices or durations;
+	// checking the kind first avoids allocating an interface value for other
+	// values. The only exception is reflect.Type, whose implementation is a
+	// pointer.
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Int64:
+	case reflect.Pointer:
+		if !v.Type().Implements(reflectTypeType) {
+			return nil
+		}
+	default:
+		return nil
+	}
 
-	if v.CanAddr() {
-		v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	if t, ok := valueAs[reflect.Type](v); ok {
+		return RawString(t.String())
 	}
 
-	if !v.CanInterface() {
+	if vs := formatSyncValue(v); vs != nil {
+		return vs
+	}
+
+	value, ok := valueInterface(v)
+	if !ok {
 		return nil
 	}
 
-	switch vv := v.Interface().(type) {
+	switch vv := value.(type) {
 	case atomic.Bool:
 		return vv.Load()
 	case atomic.Int32:
@@ -49,9 +68,48 @@ func FormatValue(v reflect.Value) any {
 	case big.Rat:
 		return RawString(vv.String())
 
+	case reflect.Value:
+		// Print the value described instead of the internals of the
+		// reflect.Value structure.
+		if !vv.IsValid() {
+			return RawString("<invalid>")
+		}
+		if inner, ok := valueInterface(vv); ok {
+			return inner
+		}
+		return RawString(fmt.Sprintf("%#v", vv))
+
 	case regexp.Regexp:
 		return RawString("/" + vv.String() + "/")
 
+	case net.IP:
+		if vv == nil {
+			return nil
+		}
+		return RawString(vv.String())
+	case net.IPNet:
+		return RawString(vv.String())
+	case net.HardwareAddr:
+		if vv == nil {
+			return nil
+		}
+		return RawString(vv.String())
+
+	case netip.Addr:
+		return RawString(vv.String())
+	case netip.Prefix:
+		return RawString(vv.String())
+	case netip.AddrPort:
+		return RawString(vv.String())
+
+	case url.URL:
+		return RawString(vv.String())
+
+	case image.Point:
+		return RawString(vv.String())
+	case image.Rectangle:
+		return RawString(vv.String())
+
 	case time.Duration:
 		return RawString(vv.String())
 	case time.Time:
@@ -60,3 +118,71 @@ func FormatValue(v reflect.Value) any {
 
 	return nil
 }
+
+func valueInterface(v reflect.Value) (any, bool) {
+	// If the value is a non-exported variable or field, we will not be able to
+	// call Interface() on it. Using the unsafe package allows us to work around
+	// it. Of course if the value is not addressable and we still cannot call
+	// Interface(), we cannot go any further and fall back to default
+	// formatting.
+
+	if v.CanAddr() {
+		v = exposedValue(v)
+	}
+
+	if !v.CanInterface() {
+		return nil, false
+	}
+
+	return v.Interface(), true
+}
+
+// accessibleValue returns a value equivalent to v which can be converted to an
+// interface value, and whose fields and elements can be addressed when v is a
+// structure or an array. Unaddressable structures and arrays, e.g. map values
+// or the content of interface values, are copied, so that formatting functions
+// and methods with a pointer receiver can be used on them and on their
+// non-exported fields.
+func accessibleValue(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		if !v.CanInterface() {
+			v = exposedValue(v)
+		}
+
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Array:
+		if v.CanInterface() {
+			v2 := reflect.New(v.Type()).Elem()
+			v2.Set(v)
+			return v2
+		}
+	}
+
+	return v
+}
+
+// valueAs returns the value converted to a specific type, usually an
+// interface, looking at methods with a pointer receiver for addressable
+// values.
+func valueAs[T any](v reflect.Value) (T, bool) {
+	var zero T
+
+	if v.CanAddr() {
+		if ptr := exposedValue(v).Addr(); ptr.CanInterface() {
+			if tv, ok := ptr.Interface().(T); ok {
+				return tv, true
+			}
+		}
+	}
+
+	value, ok := valueInterface(v)
+	if !ok {
+		return zero, false
+	}
+
+	tv, ok := value.(T)
+	return tv, ok
+}
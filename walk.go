@@ -0,0 +1,166 @@
+package pp
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// WalkFunc is called by Walk for each value, with the path of the value using
+// the syntax accepted by Get. Returning SkipValue skips the fields, elements or
+// map values of the value; returning any other error stops the walk.
+type WalkFunc func(path string, v reflect.Value) error
+
+var SkipValue = errors.New("skip value")
+
+type walkPointer struct {
+	ptr       uintptr
+	valueType reflect.Type
+	length    int
+}
+
+type walker struct {
+	p       *Printer
+	fn      WalkFunc
+	visited map[walkPointer]struct{}
+	depth   int
+}
+
+func Walk(value any, fn WalkFunc) error {
+	return DefaultPrinter.Walk(value, fn)
+}
+
+// Walk calls a function for a value and all the values nested in it, using
+// the same traversal as printing: value formatting functions are applied,
+// hidden and redacted fields are skipped, and map keys are sorted. Pointers
+// and interface values are followed, the function being called with the value
+// they point to. Values referenced from multiple places, including cyclic
+// values, are only visited once.
+func (p *Printer) Walk(value any, fn WalkFunc) error {
+	p = p.snapshot(nil)
+
+	p.reset(nil)
+	defer p.releaseBuffer()
+
+	w := walker{
+		p:       p,
+		fn:      fn,
+		visited: make(map[walkPointer]struct{}),
+	}
+
+	if err := w.walk("", reflectValue(value)); err != nil && err != SkipValue {
+		return err
+	}
+
+	return nil
+}
+
+func (w *walker) walk(path string, v reflect.Value) error {
+	if w.depth >= maxRecursionDepth {
+		return nil
+	}
+
+	w.depth++
+	defer func() { w.depth-- }()
+
+	v = accessibleValue(v)
+
+	v, rawString, _ := w.p.formatValueChain(v)
+	if rawString != nil {
+		v = reflect.ValueOf(string(*rawString))
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			return w.walk(path, v.Elem())
+		}
+
+	case reflect.Pointer:
+		if !v.IsNil() {
+			if !w.visitPointer(v) {
+				return nil
+			}
+
+			return w.walk(path, v.Elem())
+		}
+
+	case reflect.Slice, reflect.Map:
+		if !v.IsNil() && !w.visitPointer(v) {
+			return nil
+		}
+	}
+
+	if err := w.fn(path, v); err != nil {
+		if err == SkipValue {
+			return nil
+		}
+
+		return err
+	}
+
+	if v.Kind() == 0 {
+		return nil
+	}
+
+	if c, ok := collectionContent(v); ok {
+		v = c
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range w.p.structFields(v) {
+			if f.redaction != "" {
+				continue
+			}
+
+			if err := w.walk(joinPath(path, f.label()), f.value); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Array, reflect.Slice:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		for i := range v.Len() {
+			elemPath := path + "[" + strconv.Itoa(i) + "]"
+			if err := w.walk(elemPath, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		for _, key := range w.p.sortedMapKeys(v) {
+			valuePath := path + "[" + mapKeyPathString(key) + "]"
+			if err := w.walk(valuePath, v.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// visitPointer records a pointer, slice or map and returns whether it was
+// visited for the first time. The type is part of the key since a structure
+// and its first field share the same address, and the length since slices
+// can share the same array.
+func (w *walker) visitPointer(v reflect.Value) bool {
+	key := walkPointer{ptr: v.Pointer(), valueType: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.length = v.Len()
+	}
+
+	if _, found := w.visited[key]; found {
+		return false
+	}
+
+	w.visited[key] = struct{}{}
+	return true
+}
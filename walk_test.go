@@ -0,0 +1,31 @@
+package pp
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestWalkSharedValues(t *testing.T) {
+	s := []int{1}
+	m := map[string]int{"a": 1}
+
+	value := struct {
+		S1, S2 []int
+		M1, M2 map[string]int
+	}{s, s, m, m}
+
+	var paths []string
+	err := Walk(value, func(path string, v reflect.Value) error {
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedPaths := []string{"", "S1", "S1[0]", "M1", "M1[\"a\"]"}
+	if !slices.Equal(paths, expectedPaths) {
+		t.Errorf("got paths %q, expected %q", paths, expectedPaths)
+	}
+}
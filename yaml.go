@@ -0,0 +1,147 @@
+package pp
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var yamlPlainStringRE = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_ ./-]*$`)
+
+func (p *Printer) printYAMLDocument(n *Node) {
+	if yamlBlockNode(n) {
+		if n.ID > 0 {
+			p.printYAMLAnchor(n)
+			p.printNewline()
+		}
+
+		p.printYAMLBlock(n, false)
+
+		// The document printer adds the final newline itself
+		p.buf = p.buf[:len(p.buf)-1]
+	} else {
+		if n.ID > 0 {
+			p.printYAMLAnchor(n)
+			p.printByte(' ')
+		}
+
+		p.printYAMLScalar(n)
+	}
+}
+
+func (p *Printer) printYAMLBlock(n *Node, skipFirstLineStart bool) {
+	printLineStart := func() {
+		if !skipFirstLineStart {
+			p.printLineStart()
+		}
+		skipFirstLineStart = false
+	}
+
+	for _, elem := range n.Elements {
+		printLineStart()
+		p.printByte('-')
+
+		if yamlBlockNode(elem) && elem.ID == 0 {
+			p.printByte(' ')
+			p.level++
+			p.printYAMLBlock(elem, true)
+			p.level--
+			continue
+		}
+
+		p.printYAMLValue(elem)
+	}
+
+	for _, entry := range n.Entries {
+		printLineStart()
+		p.printColoredString(p.theme.FieldName, p.yamlKeyString(entry.Key))
+		p.printByte(':')
+		p.printYAMLValue(entry.Value)
+	}
+
+	for _, field := range n.Fields {
+		printLineStart()
+		p.printColoredString(p.theme.FieldName, yamlString(field.Name))
+		p.printByte(':')
+		p.printYAMLValue(field.Value)
+	}
+}
+
+func (p *Printer) printYAMLValue(n *Node) {
+	if n.ID > 0 && n.Kind != NodeRef {
+		p.printByte(' ')
+		p.printYAMLAnchor(n)
+	}
+
+	if yamlBlockNode(n) {
+		p.printNewline()
+		p.level++
+		p.printYAMLBlock(n, false)
+		p.level--
+		return
+	}
+
+	p.printByte(' ')
+	p.printYAMLScalar(n)
+	p.printNewline()
+}
+
+func (p *Printer) printYAMLScalar(n *Node) {
+	switch n.Kind {
+	case NodeScalar:
+		switch n.Scalar {
+		case ScalarNil:
+			p.printColoredString(p.theme.Keyword, "null")
+		case ScalarBool:
+			p.printColoredString(p.theme.Keyword, n.Value)
+		case ScalarNumber:
+			p.printColoredString(p.theme.Number, n.Value)
+		case ScalarString:
+			p.printColoredString(p.theme.String, yamlString(n.Value))
+		}
+	case NodeRef:
+		p.printColoredString(p.theme.Annotation, "*ref"+strconv.Itoa(n.ID))
+	case NodeSeq:
+		p.printString("[]")
+	case NodeMap, NodeStruct:
+		p.printString("{}")
+	}
+}
+
+func (p *Printer) printYAMLAnchor(n *Node) {
+	p.printColoredString(p.theme.Annotation, "&ref"+strconv.Itoa(n.ID))
+}
+
+func (p *Printer) yamlKeyString(key *Node) string {
+	if key.Kind == NodeScalar {
+		switch key.Scalar {
+		case ScalarNil:
+			return "null"
+		case ScalarBool, ScalarNumber:
+			return key.Value
+		case ScalarString:
+			return yamlString(key.Value)
+		}
+	}
+
+	return yamlString(p.jsonKeyString(key))
+}
+
+func yamlBlockNode(n *Node) bool {
+	return n.composite() && n.nbChildren() > 0
+}
+
+func yamlString(s string) string {
+	switch s {
+	case "true", "false", "yes", "no", "on", "off", "null", "~",
+		"True", "False", "Yes", "No", "On", "Off", "Null",
+		"TRUE", "FALSE", "YES", "NO", "ON", "OFF", "NULL":
+		return jsonString(s)
+	}
+
+	if yamlPlainStringRE.MatchString(s) && s[len(s)-1] != ' ' {
+		return s
+	}
+
+	// JSON strings are valid YAML double-quoted scalars
+	return jsonString(s)
+}